@@ -0,0 +1,118 @@
+package sqs
+
+import (
+	"fmt"
+)
+
+// BatchResultErrorEntry describes an entry of a batch request that failed
+// while the rest of the batch may have succeeded.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_BatchResultErrorEntry.html for more details
+type BatchResultErrorEntry struct {
+	Id          string `xml:"Id"`
+	Code        string `xml:"Code"`
+	Message     string `xml:"Message"`
+	SenderFault bool   `xml:"SenderFault"` // Whether the entry itself was at fault; such entries should not be retried as is
+}
+
+func (e BatchResultErrorEntry) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Id, e.Message, e.Code)
+}
+
+// batchOutcome records which ids of a batch request the response accounts for.
+type batchOutcome struct {
+	succeeded map[string]bool
+	failed    map[string]bool
+}
+
+func newBatchOutcome(succeeded []string, failed []BatchResultErrorEntry) batchOutcome {
+	o := batchOutcome{make(map[string]bool, len(succeeded)), make(map[string]bool, len(failed))}
+	for _, id := range succeeded {
+		o.succeeded[id] = true
+	}
+	for _, entry := range failed {
+		o.failed[entry.Id] = true
+	}
+	return o
+}
+
+func (o batchOutcome) unaccounted(id string) bool {
+	return !o.succeeded[id] && !o.failed[id]
+}
+
+func (r *SendMessageBatchResponse) outcome() batchOutcome {
+	ids := make([]string, len(r.Entries))
+	for i, entry := range r.Entries {
+		ids[i] = entry.Id
+	}
+	return newBatchOutcome(ids, r.Failed)
+}
+
+// FailedEntries returns the entries of the request that r reports as failed.
+func (r *SendMessageBatchResponse) FailedEntries(entries []SendMessageBatchRequestEntry) (failed []SendMessageBatchRequestEntry) {
+	o := r.outcome()
+	for _, entry := range entries {
+		if o.failed[entry.Id] {
+			failed = append(failed, entry)
+		}
+	}
+	return
+}
+
+// UnaccountedEntries returns the entries of the request that r reports as
+// neither succeeded nor failed.
+func (r *SendMessageBatchResponse) UnaccountedEntries(entries []SendMessageBatchRequestEntry) (unaccounted []SendMessageBatchRequestEntry) {
+	o := r.outcome()
+	for _, entry := range entries {
+		if o.unaccounted(entry.Id) {
+			unaccounted = append(unaccounted, entry)
+		}
+	}
+	return
+}
+
+// FailedEntries returns the entries of the request that r reports as failed.
+func (r *DeleteMessageBatchResponse) FailedEntries(entries []DeleteMessageBatch) (failed []DeleteMessageBatch) {
+	o := newBatchOutcome(r.Ids, r.Failed)
+	for _, entry := range entries {
+		if o.failed[entry.Id] {
+			failed = append(failed, entry)
+		}
+	}
+	return
+}
+
+// UnaccountedEntries returns the entries of the request that r reports as
+// neither succeeded nor failed.
+func (r *DeleteMessageBatchResponse) UnaccountedEntries(entries []DeleteMessageBatch) (unaccounted []DeleteMessageBatch) {
+	o := newBatchOutcome(r.Ids, r.Failed)
+	for _, entry := range entries {
+		if o.unaccounted(entry.Id) {
+			unaccounted = append(unaccounted, entry)
+		}
+	}
+	return
+}
+
+// FailedEntries returns the entries of the request that r reports as failed.
+func (r *ChangeMessageVisibilityBatchResponse) FailedEntries(entries []ChangeMessageVisibilityBatchEntry) (failed []ChangeMessageVisibilityBatchEntry) {
+	o := newBatchOutcome(r.Id, r.Failed)
+	for _, entry := range entries {
+		if o.failed[entry.Id] {
+			failed = append(failed, entry)
+		}
+	}
+	return
+}
+
+// UnaccountedEntries returns the entries of the request that r reports as
+// neither succeeded nor failed.
+func (r *ChangeMessageVisibilityBatchResponse) UnaccountedEntries(entries []ChangeMessageVisibilityBatchEntry) (unaccounted []ChangeMessageVisibilityBatchEntry) {
+	o := newBatchOutcome(r.Id, r.Failed)
+	for _, entry := range entries {
+		if o.unaccounted(entry.Id) {
+			unaccounted = append(unaccounted, entry)
+		}
+	}
+	return
+}
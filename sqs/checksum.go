@@ -0,0 +1,95 @@
+package sqs
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Parts of a message covered by an MD5 digest.
+const (
+	ChecksumBody       = "body"
+	ChecksumAttributes = "message attributes"
+)
+
+// ChecksumError reports that the MD5 digest SQS returned for a message does
+// not match the message sent or received, which means that it was corrupted
+// on the way. Set SQS.DisableChecksums to skip the verification.
+type ChecksumError struct {
+	MessageId string
+	Part      string // ChecksumBody or ChecksumAttributes
+	Expected  string // Digest computed by the client
+	Actual    string // Digest returned by SQS
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("sqs: MD5 of %s of message %s is %q, expected %q", e.Part, e.MessageId, e.Actual, e.Expected)
+}
+
+// BatchChecksumError reports the entries of a batch request whose digests
+// did not match. SQS accepted the batch, so every other entry was sent as
+// is and must not be sent again.
+type BatchChecksumError struct {
+	Entries map[string]*ChecksumError // Keyed by entry Id
+}
+
+func (e *BatchChecksumError) Error() string {
+	ids := make([]string, 0, len(e.Entries))
+	for id := range e.Entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("sqs: checksum mismatch for batch entries %s: %v", strings.Join(ids, ", "), e.Entries[ids[0]])
+}
+
+// ReceiveChecksumError reports the received messages whose digests did not
+// match. They are left out of the response, which holds the other messages,
+// and become visible again once their visibility timeout expires.
+type ReceiveChecksumError struct {
+	Messages []Message                 // The mismatched messages
+	Errors   map[string]*ChecksumError // Keyed by receipt handle
+}
+
+func (e *ReceiveChecksumError) Error() string {
+	return fmt.Sprintf("sqs: checksum mismatch for %d received messages: %v", len(e.Messages), e.Errors[e.Messages[0].ReceiptHandle])
+}
+
+// IsChecksumError reports whether err is a *ChecksumError, a
+// *BatchChecksumError or a *ReceiveChecksumError.
+func IsChecksumError(err error) bool {
+	var checksumErr *ChecksumError
+	var batchErr *BatchChecksumError
+	var receiveErr *ReceiveChecksumError
+	return errors.As(err, &checksumErr) || errors.As(err, &batchErr) || errors.As(err, &receiveErr)
+}
+
+// MessageBodyMD5 returns the hex encoded MD5 digest SQS computes over a
+// message body, as found in MD5OfMessageBody and MD5OfBody.
+func MessageBodyMD5(body string) string {
+	sum := md5.Sum([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyMessage checks the digests SQS reported for the body and attributes
+// of message id, unless checksums are disabled. A missing body digest is
+// let through, as some SQS compatible services leave it out.
+func (s *SQS) verifyMessage(id, body, bodyDigest string, attrs map[string]MessageAttributeValue, attrsDigest string) error {
+	if s.DisableChecksums {
+		return nil
+	}
+	if bodyDigest != "" {
+		if expected := MessageBodyMD5(body); bodyDigest != expected {
+			return &ChecksumError{id, ChecksumBody, expected, bodyDigest}
+		}
+	}
+	if len(attrs) == 0 && attrsDigest == "" {
+		return nil
+	}
+	if expected := MessageAttributesMD5(attrs); attrsDigest != expected {
+		return &ChecksumError{id, ChecksumAttributes, expected, attrsDigest}
+	}
+	return nil
+}
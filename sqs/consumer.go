@@ -0,0 +1,242 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Handler processes a message received by a Consumer. The message is
+// deleted from the queue when the handler returns nil, and left to become
+// visible again for another attempt otherwise.
+type Handler func(ctx context.Context, m *Message) error
+
+// ConsumerHooks are optional callbacks invoked by a Consumer as messages go
+// through it. They are called from the poller and worker goroutines, so
+// they must be safe for concurrent use and should return quickly.
+type ConsumerHooks struct {
+	OnStart   func()                      // Before the first message is requested
+	OnStop    func()                      // After the last message is handled
+	OnReceive func(m *Message)            // Before the handler runs
+	OnSuccess func(m *Message)            // After the message is deleted
+	OnFailure func(m *Message, err error) // After the handler failed
+	OnError   func(err *ConsumerError)    // When a request to SQS failed
+}
+
+// ConsumerError reports a failed request made by a Consumer.
+type ConsumerError struct {
+	Op      string   // "receive" or "delete"
+	Message *Message // The message concerned, nil for a failed receive request
+	Err     error
+}
+
+func (e *ConsumerError) Error() string {
+	if e.Message != nil {
+		return fmt.Sprintf("sqs: %s of message %s failed: %v", e.Op, e.Message.MessageId, e.Err)
+	}
+	return fmt.Sprintf("sqs: %s failed: %v", e.Op, e.Err)
+}
+
+func (e *ConsumerError) Unwrap() error {
+	return e.Err
+}
+
+// Consumer receives messages from a queue with a number of long polling
+// pollers and hands them to a number of workers running Handler. Configure
+// it through its fields before calling Run.
+type Consumer struct {
+	Queue   *Queue
+	Handler Handler
+	Hooks   ConsumerHooks
+
+	Pollers int // Concurrent ReceiveMessage calls, 1 by default
+	Workers int // Concurrent handlers, 10 by default
+
+	// Receive holds the parameters of every ReceiveMessage call.
+	// NewConsumer asks for up to 10 messages with the longest wait.
+	Receive ReceiveMessageParams
+
+	// ErrorBackoff is how long a poller waits after a failed receive
+	// before trying again, 1 second by default.
+	ErrorBackoff time.Duration
+
+	// Heartbeat, when not nil, keeps each message invisible from when it
+	// is received until its handler returns, including while it waits for
+	// a free worker. Run starts and stops it.
+	Heartbeat *Heartbeat
+
+	// Errors, when not nil, is sent every error also passed to
+	// Hooks.OnError. Errors are dropped when nobody is ready to receive
+	// them, so that a slow reader never stalls the consumer.
+	Errors chan *ConsumerError
+}
+
+// NewConsumer returns a consumer running handler on the messages of q, with
+// default settings.
+func NewConsumer(q *Queue, handler Handler) *Consumer {
+	return &Consumer{
+		Queue:        q,
+		Handler:      handler,
+		Pollers:      1,
+		Workers:      10,
+		Receive:      ReceiveMessageParams{MaxNumberOfMessages: 10, WaitTimeSeconds: MaxWaitTimeSeconds},
+		ErrorBackoff: time.Second,
+	}
+}
+
+// Run consumes messages until ctx is done. It then stops receiving, waits
+// for the handlers already running and returns. Messages that were received
+// but not yet handled are left to become visible again. Handlers are given
+// a context of their own, which is not canceled along with ctx, so that
+// they can complete.
+func (c *Consumer) Run(ctx context.Context) error {
+	if c.Queue == nil || c.Handler == nil {
+		return fmt.Errorf("sqs: consumer needs a queue and a handler")
+	}
+	if c.Heartbeat != nil {
+		if err := c.Heartbeat.validate(); err != nil {
+			return err
+		}
+	}
+	pollers, workers := c.Pollers, c.Workers
+	if pollers < 1 {
+		pollers = 1
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if c.Hooks.OnStart != nil {
+		c.Hooks.OnStart()
+	}
+
+	messages := make(chan *Message)
+	var polling, working, beating sync.WaitGroup
+	beatCtx, stopBeating := context.WithCancel(context.Background())
+	if c.Heartbeat != nil {
+		beating.Add(1)
+		go func() {
+			defer beating.Done()
+			c.Heartbeat.Run(beatCtx)
+		}()
+	}
+	for i := 0; i < pollers; i++ {
+		polling.Add(1)
+		go func() {
+			defer polling.Done()
+			c.poll(ctx, messages)
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		working.Add(1)
+		go func() {
+			defer working.Done()
+			for m := range messages {
+				c.handle(m)
+			}
+		}()
+	}
+
+	polling.Wait()
+	close(messages)
+	working.Wait()
+	stopBeating()
+	beating.Wait()
+
+	if c.Hooks.OnStop != nil {
+		c.Hooks.OnStop()
+	}
+	return nil
+}
+
+func (c *Consumer) poll(ctx context.Context, messages chan<- *Message) {
+	for ctx.Err() == nil {
+		resp, err := c.Queue.ReceiveMessageWithParamsContext(ctx, c.Receive)
+		if checksumErr, ok := err.(*ReceiveChecksumError); ok {
+			// Only the corrupted messages are lost; they are left to
+			// become visible again while the others are handled.
+			for i := range checksumErr.Messages {
+				m := &checksumErr.Messages[i]
+				c.fail(&ConsumerError{Op: "receive", Message: m, Err: checksumErr.Errors[m.ReceiptHandle]})
+			}
+			err = nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.fail(&ConsumerError{Op: "receive", Err: err})
+			select {
+			case <-time.After(c.ErrorBackoff):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		if c.Heartbeat != nil {
+			// Messages may wait for a free worker, so their lease starts now.
+			for i := range resp.Messages {
+				c.Heartbeat.Track(resp.Messages[i].ReceiptHandle)
+			}
+		}
+		for i := range resp.Messages {
+			select {
+			case messages <- &resp.Messages[i]:
+			case <-ctx.Done():
+				// Let the messages nobody will handle become visible again.
+				if c.Heartbeat != nil {
+					for _, m := range resp.Messages[i:] {
+						c.Heartbeat.Release(m.ReceiptHandle)
+					}
+				}
+				return
+			}
+		}
+	}
+}
+
+func (c *Consumer) handle(m *Message) {
+	if c.Hooks.OnReceive != nil {
+		c.Hooks.OnReceive(m)
+	}
+	ctx := context.Background()
+	err := c.call(ctx, m)
+	if c.Heartbeat != nil {
+		c.Heartbeat.Release(m.ReceiptHandle)
+	}
+	if err != nil {
+		if c.Hooks.OnFailure != nil {
+			c.Hooks.OnFailure(m, err)
+		}
+		return
+	}
+	if _, err := c.Queue.DeleteMessageContext(ctx, m.ReceiptHandle); err != nil {
+		c.fail(&ConsumerError{Op: "delete", Message: m, Err: err})
+		return
+	}
+	if c.Hooks.OnSuccess != nil {
+		c.Hooks.OnSuccess(m)
+	}
+}
+
+// call runs the handler, turning a panic into an error so that one bad
+// message does not bring the whole consumer down.
+func (c *Consumer) call(ctx context.Context, m *Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sqs: handler panicked: %v", r)
+		}
+	}()
+	return c.Handler(ctx, m)
+}
+
+func (c *Consumer) fail(err *ConsumerError) {
+	if c.Hooks.OnError != nil {
+		c.Hooks.OnError(err)
+	}
+	if c.Errors != nil {
+		select {
+		case c.Errors <- err:
+		default:
+		}
+	}
+}
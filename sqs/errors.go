@@ -0,0 +1,163 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"syscall"
+)
+
+// Error codes returned by SQS in Error.Code.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/CommonErrors.html for more details
+const (
+	ErrCodeNonExistentQueue             = "AWS.SimpleQueueService.NonExistentQueue"
+	ErrCodeQueueDeletedRecently         = "AWS.SimpleQueueService.QueueDeletedRecently"
+	ErrCodeQueueAlreadyExists           = "QueueAlreadyExists"
+	ErrCodePurgeQueueInProgress         = "AWS.SimpleQueueService.PurgeQueueInProgress"
+	ErrCodeMessageNotInflight           = "AWS.SimpleQueueService.MessageNotInflight"
+	ErrCodeReceiptHandleIsInvalid       = "ReceiptHandleIsInvalid"
+	ErrCodeOverLimit                    = "OverLimit"
+	ErrCodeInvalidParameterValue        = "InvalidParameterValue"
+	ErrCodeInvalidAttributeName         = "InvalidAttributeName"
+	ErrCodeEmptyBatchRequest            = "AWS.SimpleQueueService.EmptyBatchRequest"
+	ErrCodeTooManyEntriesInBatchRequest = "AWS.SimpleQueueService.TooManyEntriesInBatchRequest"
+	ErrCodeBatchEntryIdsNotDistinct     = "AWS.SimpleQueueService.BatchEntryIdsNotDistinct"
+	ErrCodeBatchRequestTooLong          = "AWS.SimpleQueueService.BatchRequestTooLong"
+	ErrCodeInvalidBatchEntryId          = "AWS.SimpleQueueService.InvalidBatchEntryId"
+	ErrCodeAccessDenied                 = "AccessDenied"
+	ErrCodeSignatureDoesNotMatch        = "SignatureDoesNotMatch"
+	ErrCodeRequestTimeTooSkewed         = "RequestTimeTooSkewed"
+	ErrCodeRequestExpired               = "RequestExpired"
+	ErrCodeThrottling                   = "Throttling"
+	ErrCodeInternalError                = "InternalError"
+	ErrCodeServiceUnavailable           = "ServiceUnavailable"
+	ErrCodeUnsupportedOperation         = "AWS.SimpleQueueService.UnsupportedOperation"
+	ErrCodeKmsAccessDenied              = "KmsAccessDenied"
+	ErrCodeResourceNotFound             = "ResourceNotFoundException"
+)
+
+// Sentinel errors to compare SQS errors with using errors.Is. An *Error
+// matches a sentinel when their codes agree, whatever its message.
+var (
+	ErrNonExistentQueue         = &Error{Code: ErrCodeNonExistentQueue, Message: "queue does not exist"}
+	ErrQueueDeletedRecently     = &Error{Code: ErrCodeQueueDeletedRecently, Message: "queue was deleted recently"}
+	ErrQueueAlreadyExists       = &Error{Code: ErrCodeQueueAlreadyExists, Message: "queue already exists"}
+	ErrPurgeQueueInProgress     = &Error{Code: ErrCodePurgeQueueInProgress, Message: "queue was purged recently"}
+	ErrMessageNotInflight       = &Error{Code: ErrCodeMessageNotInflight, Message: "message is not in flight"}
+	ErrReceiptHandleIsInvalid   = &Error{Code: ErrCodeReceiptHandleIsInvalid, Message: "receipt handle is invalid"}
+	ErrOverLimit                = &Error{Code: ErrCodeOverLimit, Message: "limit exceeded"}
+	ErrInvalidParameterValue    = &Error{Code: ErrCodeInvalidParameterValue, Message: "invalid parameter value"}
+	ErrInvalidAttributeName     = &Error{Code: ErrCodeInvalidAttributeName, Message: "invalid attribute name"}
+	ErrAccessDenied             = &Error{Code: ErrCodeAccessDenied, Message: "access denied"}
+	ErrSignatureDoesNotMatch    = &Error{Code: ErrCodeSignatureDoesNotMatch, Message: "signature does not match"}
+	ErrRequestTimeTooSkewed     = &Error{Code: ErrCodeRequestTimeTooSkewed, Message: "request time too skewed"}
+	ErrThrottling               = &Error{Code: ErrCodeThrottling, Message: "request throttled"}
+	ErrInternalError            = &Error{Code: ErrCodeInternalError, Message: "internal error"}
+	ErrServiceUnavailable       = &Error{Code: ErrCodeServiceUnavailable, Message: "service unavailable"}
+	ErrTooManyEntriesInBatch    = &Error{Code: ErrCodeTooManyEntriesInBatchRequest, Message: "too many entries in batch request"}
+	ErrBatchEntryIdsNotDistinct = &Error{Code: ErrCodeBatchEntryIdsNotDistinct, Message: "batch entry ids not distinct"}
+)
+
+// Codes SQS has used for the same condition over time, mapped to the one
+// the sentinels above carry.
+var codeAliases = map[string]string{
+	"QueueDoesNotExist":                        ErrCodeNonExistentQueue,
+	"AWS.SimpleQueueService.QueueDoesNotExist": ErrCodeNonExistentQueue,
+	"QueueDeletedRecently":                     ErrCodeQueueDeletedRecently,
+	"QueueNameExists":                          ErrCodeQueueAlreadyExists,
+	"PurgeQueueInProgress":                     ErrCodePurgeQueueInProgress,
+	"MessageNotInflight":                       ErrCodeMessageNotInflight,
+	"RequestThrottled":                         ErrCodeThrottling,
+	"ThrottlingException":                      ErrCodeThrottling,
+	"InternalFailure":                          ErrCodeInternalError,
+	"AccessDeniedException":                    ErrCodeAccessDenied,
+}
+
+func canonicalCode(code string) string {
+	if c, ok := codeAliases[code]; ok {
+		return c
+	}
+	return code
+}
+
+// Is reports whether err carries the same error code as target, which lets
+// errors.Is match an SQS error against the sentinels of this package.
+func (err *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && canonicalCode(err.Code) == canonicalCode(t.Code)
+}
+
+// Unwrap returns the next error SQS returned along with err, if any, so that
+// errors.Is and errors.As look at all of them.
+func (err *Error) Unwrap() error {
+	if err.Next == nil {
+		return nil
+	}
+	return err.Next
+}
+
+// All returns err followed by every error chained after it.
+func (err *Error) All() []*Error {
+	var all []*Error
+	for e := err; e != nil; e = e.Next {
+		all = append(all, e)
+	}
+	return all
+}
+
+// Codes of errors that may go away if the request is simply sent again.
+var retryableCodes = map[string]bool{
+	ErrCodeThrottling:         true,
+	ErrCodeInternalError:      true,
+	ErrCodeServiceUnavailable: true,
+	"RequestTimeout":          true,
+}
+
+// IsRetryable reports whether the request that failed with err may succeed
+// if sent again: throttling, server side failures, and requests that could
+// not be sent because the connection timed out, was refused, or was reset
+// or closed. Other transport failures, such as an untrusted certificate,
+// are permanent. Errors met while reading a successful response are never
+// retried, as SQS already carried out the request.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var sqsErr *Error
+	if errors.As(err, &sqsErr) {
+		return sqsErr.StatusCode >= 500 || sqsErr.StatusCode == 429 || retryableCodes[canonicalCode(sqsErr.Code)]
+	}
+	// Only failures of the HTTP exchange itself come as a *url.Error.
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	if urlErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// IsNotFound reports whether err means that the queue does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNonExistentQueue)
+}
+
+// IsPurgeInProgress reports whether err means that the queue was already
+// purged within the last PurgeWindow.
+func IsPurgeInProgress(err error) bool {
+	return errors.Is(err, ErrPurgeQueueInProgress)
+}
+
+// IsThrottling reports whether err means that requests are being sent
+// faster than SQS accepts them.
+func IsThrottling(err error) bool {
+	var sqsErr *Error
+	if errors.As(err, &sqsErr) && sqsErr.StatusCode == 429 {
+		return true
+	}
+	return errors.Is(err, ErrThrottling)
+}
@@ -0,0 +1,58 @@
+package sqs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// FifoSuffix ends the name of every FIFO queue.
+const FifoSuffix = ".fifo"
+
+// IsFifo reports whether q is a FIFO queue.
+func (q *Queue) IsFifo() bool {
+	return strings.HasSuffix(q.Url, FifoSuffix)
+}
+
+// ContentBasedDeduplicationId returns the deduplication id SQS derives from
+// body on queues with ContentBasedDeduplication enabled. Passing it as
+// MessageDeduplicationId gives the same behaviour on queues without it.
+func ContentBasedDeduplicationId(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateQueueName checks that a queue created with the FifoQueue
+// attribute set is named like a FIFO queue, and the other way round.
+func validateQueueName(name string, attributes []Attribute) error {
+	fifo := false
+	for _, attribute := range attributes {
+		if attribute.Name == "FifoQueue" {
+			fifo = strings.EqualFold(attribute.Value, "true")
+		}
+	}
+	switch {
+	case fifo && !strings.HasSuffix(name, FifoSuffix):
+		return fmt.Errorf("sqs: FIFO queue name %q must end in %q", name, FifoSuffix)
+	case !fifo && strings.HasSuffix(name, FifoSuffix):
+		return fmt.Errorf("sqs: queue name %q ends in %q but FifoQueue is not set", name, FifoSuffix)
+	}
+	return nil
+}
+
+// validateFifoMessage checks the FIFO parameters of a message sent to q.
+// FIFO queues only support a queue wide delay, so a per-message delay is
+// refused rather than dropped.
+func (q *Queue) validateFifoMessage(groupId string, delaySeconds int) error {
+	if !q.IsFifo() {
+		return nil
+	}
+	if groupId == "" {
+		return fmt.Errorf("sqs: a MessageGroupId is required to send to FIFO queue %s", q.Url)
+	}
+	if delaySeconds != 0 {
+		return fmt.Errorf("sqs: FIFO queue %s does not support per-message DelaySeconds", q.Url)
+	}
+	return nil
+}
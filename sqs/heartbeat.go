@@ -0,0 +1,165 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxVisibilityTimeout is the longest a received message can be kept
+// invisible, counting from when it was received.
+const MaxVisibilityTimeout = 12 * time.Hour
+
+// maxBatchEntries is the number of entries a batch request takes at most.
+const maxBatchEntries = 10
+
+// Heartbeat keeps messages invisible while they are being processed by
+// periodically extending their visibility timeout, so that a slow handler
+// does not see its message delivered to someone else. Messages are tracked
+// from Track until released, or until they have been held for MaxLease.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-visibility-timeout.html for more details
+type Heartbeat struct {
+	Queue *Queue
+
+	// VisibilityTimeout is how far each beat pushes the visibility of the
+	// tracked messages, and Interval how often beats happen. Interval must
+	// be comfortably shorter than VisibilityTimeout; NewHeartbeat sets it
+	// to half of it.
+	VisibilityTimeout time.Duration
+	Interval          time.Duration
+
+	// MaxLease bounds how long a message is kept invisible after Track.
+	// It defaults to MaxVisibilityTimeout.
+	MaxLease time.Duration
+
+	// OnError, when not nil, is called with the errors of failed beats.
+	OnError func(err error)
+
+	mu     sync.Mutex
+	leases map[string]time.Time // Receipt handle to lease deadline
+}
+
+// NewHeartbeat returns a heartbeat extending the visibility of messages of
+// q by visibilityTimeout at a time.
+func NewHeartbeat(q *Queue, visibilityTimeout time.Duration) *Heartbeat {
+	return &Heartbeat{
+		Queue:             q,
+		VisibilityTimeout: visibilityTimeout,
+		Interval:          visibilityTimeout / 2,
+		MaxLease:          MaxVisibilityTimeout,
+	}
+}
+
+// Track starts extending the visibility of the message received with
+// receiptHandle. It returns a function that stops it, to be called once
+// the message has been handled.
+func (h *Heartbeat) Track(receiptHandle string) (release func()) {
+	lease := h.MaxLease
+	if lease <= 0 {
+		lease = MaxVisibilityTimeout
+	}
+	h.mu.Lock()
+	if h.leases == nil {
+		h.leases = make(map[string]time.Time)
+	}
+	h.leases[receiptHandle] = time.Now().Add(lease)
+	h.mu.Unlock()
+	return func() { h.Release(receiptHandle) }
+}
+
+// Release stops extending the visibility of the message received with
+// receiptHandle.
+func (h *Heartbeat) Release(receiptHandle string) {
+	h.mu.Lock()
+	delete(h.leases, receiptHandle)
+	h.mu.Unlock()
+}
+
+// Len returns the number of messages being tracked.
+func (h *Heartbeat) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.leases)
+}
+
+// Run beats every Interval until ctx is done.
+func (h *Heartbeat) Run(ctx context.Context) error {
+	if err := h.validate(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(h.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.Beat(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (h *Heartbeat) validate() error {
+	if h.Queue == nil || h.Interval <= 0 || h.VisibilityTimeout < time.Second {
+		return fmt.Errorf("sqs: heartbeat needs a queue, a positive interval and a visibility timeout of at least a second")
+	}
+	return nil
+}
+
+// Beat extends the visibility of every tracked message once, using as few
+// ChangeMessageVisibilityBatch requests as possible. Messages whose lease is
+// over are dropped without being extended, and so are those SQS refuses to
+// extend, e.g. because they were deleted meanwhile.
+func (h *Heartbeat) Beat(ctx context.Context) {
+	now := time.Now()
+	var entries []ChangeMessageVisibilityBatchEntry
+	h.mu.Lock()
+	for handle, deadline := range h.leases {
+		remaining := deadline.Sub(now)
+		if remaining <= 0 {
+			delete(h.leases, handle)
+			continue
+		}
+		timeout := h.VisibilityTimeout
+		if remaining < timeout {
+			timeout = remaining
+		}
+		seconds := int((timeout + time.Second - 1) / time.Second)
+		entries = append(entries, ChangeMessageVisibilityBatchEntry{strconv.Itoa(len(entries)), handle, seconds})
+	}
+	h.mu.Unlock()
+
+	for len(entries) > 0 {
+		n := len(entries)
+		if n > maxBatchEntries {
+			n = maxBatchEntries
+		}
+		h.extend(ctx, entries[:n])
+		entries = entries[n:]
+	}
+}
+
+func (h *Heartbeat) extend(ctx context.Context, entries []ChangeMessageVisibilityBatchEntry) {
+	resp, err := h.Queue.ChangeMessageVisibilityBatchContext(ctx, entries)
+	if err != nil {
+		h.fail(err)
+		return
+	}
+	for _, failed := range resp.Failed {
+		for _, entry := range entries {
+			if entry.Id == failed.Id {
+				h.Release(entry.ReceiptHandle)
+			}
+		}
+		h.fail(failed)
+	}
+}
+
+func (h *Heartbeat) fail(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
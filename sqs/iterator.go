@@ -0,0 +1,87 @@
+package sqs
+
+import (
+	"context"
+)
+
+// listQueuesPageSize is the largest page ListQueues returns.
+const listQueuesPageSize = 1000
+
+// QueueIterator walks through the queues of an account, fetching a page of
+// queue URLs only when the previous one is used up. It is used like a
+// bufio.Scanner:
+//
+//	it := s.Queues("")
+//	for it.Next() {
+//		q := it.Queue()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// To stop early simply stop calling Next; no further requests are made.
+// A QueueIterator must not be used by several goroutines at once.
+type QueueIterator struct {
+	sqs       *SQS
+	ctx       context.Context
+	prefix    string
+	pageSize  int
+	urls      []string
+	nextToken string
+	queue     *Queue
+	done      bool
+	err       error
+}
+
+// Queues returns an iterator over all the queues whose name begins with
+// queueNamePrefix, or over all queues when the prefix is empty.
+func (s *SQS) Queues(queueNamePrefix string) *QueueIterator {
+	return s.QueuesContext(context.Background(), queueNamePrefix)
+}
+
+// QueuesContext is like Queues but carries ctx into the requests. Canceling
+// ctx also stops the iteration.
+func (s *SQS) QueuesContext(ctx context.Context, queueNamePrefix string) *QueueIterator {
+	return &QueueIterator{sqs: s, ctx: ctx, prefix: queueNamePrefix, pageSize: listQueuesPageSize}
+}
+
+// PageSize sets the number of queues requested per page, from 1 to 1000.
+// It must be called before the first call to Next.
+func (it *QueueIterator) PageSize(n int) *QueueIterator {
+	it.pageSize = n
+	return it
+}
+
+// Next advances the iterator to the next queue, which is then available
+// through Queue. It returns false once the queues are exhausted or a
+// request fails, in which case Err tells why.
+func (it *QueueIterator) Next() bool {
+	it.queue = nil
+	for len(it.urls) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		resp, err := it.sqs.ListQueuesPageContext(it.ctx, it.prefix, it.pageSize, it.nextToken)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.urls = resp.QueueUrl
+		it.nextToken = resp.NextToken
+		it.done = it.nextToken == ""
+	}
+	it.queue = &Queue{it.sqs, it.urls[0]}
+	it.urls = it.urls[1:]
+	return true
+}
+
+// Queue returns the queue the last call to Next advanced to.
+func (it *QueueIterator) Queue() *Queue {
+	return it.queue
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *QueueIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,168 @@
+package sqs
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Data types of message attribute values. A custom type may be appended to
+// any of them after a dot, as in "Number.float" or "Binary.gif".
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-message-metadata.html for more details
+const (
+	StringDataType = "String"
+	NumberDataType = "Number"
+	BinaryDataType = "Binary"
+)
+
+// MessageAttributeValue is the typed value of a message attribute.
+type MessageAttributeValue struct {
+	DataType    string
+	StringValue string // Set for String and Number values
+	BinaryValue []byte // Set for Binary values
+}
+
+// MessageAttribute is a named attribute attached to a message by its sender.
+type MessageAttribute struct {
+	Name  string
+	Value MessageAttributeValue
+}
+
+// StringAttribute returns a String message attribute value.
+func StringAttribute(value string) MessageAttributeValue {
+	return MessageAttributeValue{DataType: StringDataType, StringValue: value}
+}
+
+// NumberAttribute returns a Number message attribute value. Numbers are
+// sent as strings so that no precision is lost on the way.
+func NumberAttribute(value string) MessageAttributeValue {
+	return MessageAttributeValue{DataType: NumberDataType, StringValue: value}
+}
+
+// IntAttribute returns a Number message attribute value holding n.
+func IntAttribute(n int64) MessageAttributeValue {
+	return NumberAttribute(strconv.FormatInt(n, 10))
+}
+
+// BinaryAttribute returns a Binary message attribute value.
+func BinaryAttribute(value []byte) MessageAttributeValue {
+	return MessageAttributeValue{DataType: BinaryDataType, BinaryValue: value}
+}
+
+// WithCustomType returns a copy of v whose data type carries the given
+// custom type suffix, e.g. StringAttribute(id).WithCustomType("uuid").
+func (v MessageAttributeValue) WithCustomType(customType string) MessageAttributeValue {
+	v.DataType = v.BaseType() + "." + customType
+	return v
+}
+
+// BaseType returns the data type of v without any custom type suffix.
+func (v MessageAttributeValue) BaseType() string {
+	if i := strings.Index(v.DataType, "."); i >= 0 {
+		return v.DataType[:i]
+	}
+	return v.DataType
+}
+
+// CustomType returns the custom type suffix of v's data type, if any.
+func (v MessageAttributeValue) CustomType() string {
+	if i := strings.Index(v.DataType, "."); i >= 0 {
+		return v.DataType[i+1:]
+	}
+	return ""
+}
+
+// UnmarshalXML decodes a message attribute value, whose binary form is
+// base64 encoded on the wire.
+func (v *MessageAttributeValue) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		DataType    string
+		StringValue string
+		BinaryValue string
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	v.DataType = raw.DataType
+	v.StringValue = raw.StringValue
+	v.BinaryValue = nil
+	if raw.BinaryValue != "" {
+		b, err := base64.StdEncoding.DecodeString(raw.BinaryValue)
+		if err != nil {
+			return err
+		}
+		v.BinaryValue = b
+	}
+	return nil
+}
+
+// MessageAttributes returns the message attributes of m keyed by name.
+func (m *Message) MessageAttributes() map[string]MessageAttributeValue {
+	attrs := make(map[string]MessageAttributeValue, len(m.MessageAttribute))
+	for _, a := range m.MessageAttribute {
+		attrs[a.Name] = a.Value
+	}
+	return attrs
+}
+
+// addMessageAttributeParams encodes attrs into params, naming each
+// parameter after prefix, e.g. "MessageAttribute" or
+// "SendMessageBatchRequestEntry.1.MessageAttribute".
+func addMessageAttributeParams(params map[string]string, prefix string, attrs map[string]MessageAttributeValue) {
+	for i, name := range sortedAttributeNames(attrs) {
+		value := attrs[name]
+		n := prefix + "." + strconv.Itoa(i+1)
+		params[n+".Name"] = name
+		params[n+".Value.DataType"] = value.DataType
+		if value.BaseType() == BinaryDataType {
+			params[n+".Value.BinaryValue"] = base64.StdEncoding.EncodeToString(value.BinaryValue)
+		} else {
+			params[n+".Value.StringValue"] = value.StringValue
+		}
+	}
+}
+
+func sortedAttributeNames(attrs map[string]MessageAttributeValue) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MessageAttributesMD5 returns the hex encoded MD5 digest SQS computes over
+// a set of message attributes, as found in MD5OfMessageAttributes.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-message-metadata.html for more details
+func MessageAttributesMD5(attrs map[string]MessageAttributeValue) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	hash := md5.New()
+	writeField := func(b []byte) {
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(b)))
+		hash.Write(size[:])
+		hash.Write(b)
+	}
+	for _, name := range sortedAttributeNames(attrs) {
+		value := attrs[name]
+		writeField([]byte(name))
+		writeField([]byte(value.DataType))
+		if value.BaseType() == BinaryDataType {
+			hash.Write([]byte{2})
+			writeField(value.BinaryValue)
+		} else {
+			hash.Write([]byte{1})
+			writeField([]byte(value.StringValue))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
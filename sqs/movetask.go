@@ -0,0 +1,176 @@
+package sqs
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// MessageMoveTaskStatus is the state of a message move task.
+type MessageMoveTaskStatus string
+
+const (
+	MessageMoveTaskRunning    MessageMoveTaskStatus = "RUNNING"
+	MessageMoveTaskCompleted  MessageMoveTaskStatus = "COMPLETED"
+	MessageMoveTaskCancelling MessageMoveTaskStatus = "CANCELLING"
+	MessageMoveTaskCancelled  MessageMoveTaskStatus = "CANCELLED"
+	MessageMoveTaskFailed     MessageMoveTaskStatus = "FAILED"
+)
+
+// Done reports whether a task in this state has stopped moving messages
+// for good.
+func (s MessageMoveTaskStatus) Done() bool {
+	return s == MessageMoveTaskCompleted || s == MessageMoveTaskCancelled || s == MessageMoveTaskFailed
+}
+
+// MessageMoveTask describes a task moving messages out of a dead-letter queue.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListMessageMoveTasksResultEntry.html for more details
+type MessageMoveTask struct {
+	TaskHandle                        string                `xml:"TaskHandle"` // Only set while the task is running
+	Status                            MessageMoveTaskStatus `xml:"Status"`
+	SourceArn                         string                `xml:"SourceArn"`
+	DestinationArn                    string                `xml:"DestinationArn"`
+	MaxNumberOfMessagesPerSecond      int                   `xml:"MaxNumberOfMessagesPerSecond"`
+	ApproximateNumberOfMessagesMoved  int64                 `xml:"ApproximateNumberOfMessagesMoved"`
+	ApproximateNumberOfMessagesToMove int64                 `xml:"ApproximateNumberOfMessagesToMove"`
+	FailureReason                     string                `xml:"FailureReason"`
+	StartedTimestamp                  int64                 `xml:"StartedTimestamp"` // Milliseconds since the epoch
+}
+
+// Started returns the time the task started.
+func (t *MessageMoveTask) Started() time.Time {
+	return time.Unix(0, t.StartedTimestamp*int64(time.Millisecond))
+}
+
+// Response to a StartMessageMoveTask request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_StartMessageMoveTask.html for more details
+type StartMessageMoveTaskResponse struct {
+	TaskHandle string `xml:"StartMessageMoveTaskResult>TaskHandle"`
+	ResponseMetadata
+}
+
+// Response to a ListMessageMoveTasks request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListMessageMoveTasks.html for more details
+type ListMessageMoveTasksResponse struct {
+	Tasks []MessageMoveTask `xml:"ListMessageMoveTasksResult>ListMessageMoveTasksResultEntry"`
+	ResponseMetadata
+}
+
+// Response to a CancelMessageMoveTask request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_CancelMessageMoveTask.html for more details
+type CancelMessageMoveTaskResponse struct {
+	ApproximateNumberOfMessagesMoved int64 `xml:"CancelMessageMoveTaskResult>ApproximateNumberOfMessagesMoved"`
+	ResponseMetadata
+}
+
+// StartMessageMoveTask action starts moving the messages of the dead-letter queue sourceArn to
+// destinationArn, or back to their original source queues when destinationArn is empty. A zero
+// maxNumberOfMessagesPerSecond lets SQS choose the rate.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_StartMessageMoveTask.html for more details
+func (s *SQS) StartMessageMoveTask(sourceArn, destinationArn string, maxNumberOfMessagesPerSecond int) (resp *StartMessageMoveTaskResponse, err error) {
+	return s.StartMessageMoveTaskContext(context.Background(), sourceArn, destinationArn, maxNumberOfMessagesPerSecond)
+}
+
+// StartMessageMoveTaskContext is like StartMessageMoveTask but carries ctx into the request.
+func (s *SQS) StartMessageMoveTaskContext(ctx context.Context, sourceArn, destinationArn string, maxNumberOfMessagesPerSecond int) (resp *StartMessageMoveTaskResponse, err error) {
+	resp = &StartMessageMoveTaskResponse{}
+	params := makeParams("StartMessageMoveTask")
+
+	params["SourceArn"] = sourceArn
+	if destinationArn != "" {
+		params["DestinationArn"] = destinationArn
+	}
+	if maxNumberOfMessagesPerSecond != 0 {
+		params["MaxNumberOfMessagesPerSecond"] = strconv.Itoa(maxNumberOfMessagesPerSecond)
+	}
+
+	err = s.query(ctx, "", params, resp)
+	return
+}
+
+// ListMessageMoveTasks action returns the most recent message move tasks, up to maxResults, that
+// moved messages out of the dead-letter queue sourceArn. A zero maxResults returns only the latest.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListMessageMoveTasks.html for more details
+func (s *SQS) ListMessageMoveTasks(sourceArn string, maxResults int) (resp *ListMessageMoveTasksResponse, err error) {
+	return s.ListMessageMoveTasksContext(context.Background(), sourceArn, maxResults)
+}
+
+// ListMessageMoveTasksContext is like ListMessageMoveTasks but carries ctx into the request.
+func (s *SQS) ListMessageMoveTasksContext(ctx context.Context, sourceArn string, maxResults int) (resp *ListMessageMoveTasksResponse, err error) {
+	resp = &ListMessageMoveTasksResponse{}
+	params := makeParams("ListMessageMoveTasks")
+
+	params["SourceArn"] = sourceArn
+	if maxResults != 0 {
+		params["MaxResults"] = strconv.Itoa(maxResults)
+	}
+
+	err = s.query(ctx, "", params, resp)
+	return
+}
+
+// CancelMessageMoveTask action stops a running message move task. Messages already moved are not
+// moved back.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_CancelMessageMoveTask.html for more details
+func (s *SQS) CancelMessageMoveTask(taskHandle string) (resp *CancelMessageMoveTaskResponse, err error) {
+	return s.CancelMessageMoveTaskContext(context.Background(), taskHandle)
+}
+
+// CancelMessageMoveTaskContext is like CancelMessageMoveTask but carries ctx into the request.
+func (s *SQS) CancelMessageMoveTaskContext(ctx context.Context, taskHandle string) (resp *CancelMessageMoveTaskResponse, err error) {
+	resp = &CancelMessageMoveTaskResponse{}
+	params := makeParams("CancelMessageMoveTask")
+
+	params["TaskHandle"] = taskHandle
+
+	err = s.query(ctx, "", params, resp)
+	return
+}
+
+// Redrive is a helper function for StartMessageMoveTask action that moves the messages of the
+// queue, a dead-letter queue, to destination, or back to their source queues when destination
+// is nil.
+func (q *Queue) Redrive(destination *Queue, maxNumberOfMessagesPerSecond int) (resp *StartMessageMoveTaskResponse, err error) {
+	return q.RedriveContext(context.Background(), destination, maxNumberOfMessagesPerSecond)
+}
+
+// RedriveContext is like Redrive but carries ctx into the requests.
+func (q *Queue) RedriveContext(ctx context.Context, destination *Queue, maxNumberOfMessagesPerSecond int) (resp *StartMessageMoveTaskResponse, err error) {
+	sourceArn, err := q.ArnContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	destinationArn := ""
+	if destination != nil {
+		if destinationArn, err = destination.ArnContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return q.SQS.StartMessageMoveTaskContext(ctx, sourceArn, destinationArn, maxNumberOfMessagesPerSecond)
+}
+
+// RedriveTasks is a helper function for ListMessageMoveTasks action that returns the most recent
+// message move tasks of the queue, a dead-letter queue.
+func (q *Queue) RedriveTasks(maxResults int) ([]MessageMoveTask, error) {
+	return q.RedriveTasksContext(context.Background(), maxResults)
+}
+
+// RedriveTasksContext is like RedriveTasks but carries ctx into the requests.
+func (q *Queue) RedriveTasksContext(ctx context.Context, maxResults int) ([]MessageMoveTask, error) {
+	sourceArn, err := q.ArnContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := q.SQS.ListMessageMoveTasksContext(ctx, sourceArn, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
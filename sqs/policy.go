@@ -0,0 +1,440 @@
+package sqs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// PolicyVersion is the current version of the access policy language.
+const PolicyVersion = "2012-10-17"
+
+// Effects of a policy statement.
+const (
+	EffectAllow = "Allow"
+	EffectDeny  = "Deny"
+)
+
+// Condition keys commonly used in queue policies, e.g. to let an SNS topic
+// or S3 bucket send messages to the queue.
+const (
+	ConditionSourceArn     = "aws:SourceArn"
+	ConditionSourceAccount = "aws:SourceAccount"
+)
+
+// Policy is an access policy document, as held by the Policy attribute of a
+// queue.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-using-identity-based-policies.html for more details
+type Policy struct {
+	Version   string       `json:"Version,omitempty"`
+	Id        string       `json:"Id,omitempty"`
+	Statement []*Statement `json:"Statement"`
+}
+
+// Statement is a single permission of a policy. The Not fields apply the
+// statement to everything but the given principals, actions or resources.
+type Statement struct {
+	Sid          string     `json:"Sid,omitempty"`
+	Effect       string     `json:"Effect"`
+	Principal    *Principal `json:"Principal,omitempty"`
+	NotPrincipal *Principal `json:"NotPrincipal,omitempty"`
+	Action       StringList `json:"Action,omitempty"`
+	NotAction    StringList `json:"NotAction,omitempty"`
+	Resource     StringList `json:"Resource,omitempty"`
+	NotResource  StringList `json:"NotResource,omitempty"`
+	Condition    Conditions `json:"Condition,omitempty"`
+}
+
+// Principal is who a statement applies to: anyone, or the given AWS accounts,
+// users and roles, AWS services, canonical users and federated identity
+// providers. Principal types without a field of their own are kept in Other,
+// keyed by type, so that they survive a round trip.
+type Principal struct {
+	Anyone        bool
+	AWS           StringList
+	Service       StringList
+	CanonicalUser StringList
+	Federated     StringList
+	Other         map[string]StringList
+}
+
+// StringList is a list of strings, written as a single string when it has
+// one element, as policy documents usually do.
+type StringList []string
+
+// Conditions maps condition operators, e.g. "ArnEquals", to the condition
+// keys they test and the values they accept.
+type Conditions map[string]map[string]ConditionValues
+
+// ConditionValues lists the values a condition key is tested against. They
+// are kept as the JSON scalars they were written as: strings, booleans, or
+// numbers as json.Number. A single value is written as a plain scalar.
+type ConditionValues []interface{}
+
+// NewPolicy returns an empty policy with the given id.
+func NewPolicy(id string) *Policy {
+	return &Policy{Version: PolicyVersion, Id: id}
+}
+
+// AnyPrincipal returns the principal that matches everyone.
+func AnyPrincipal() *Principal {
+	return &Principal{Anyone: true}
+}
+
+// AWSPrincipal returns the principal matching the given AWS account ids or
+// user and role ARNs.
+func AWSPrincipal(arns ...string) *Principal {
+	return &Principal{AWS: arns}
+}
+
+// ServicePrincipal returns the principal matching the given AWS services,
+// e.g. "sns.amazonaws.com".
+func ServicePrincipal(services ...string) *Principal {
+	return &Principal{Service: services}
+}
+
+// Allow adds a statement to p that lets principal run actions, e.g.
+// "sqs:SendMessage", on resource, and returns it so conditions can be added.
+func (p *Policy) Allow(sid string, principal *Principal, resource string, actions ...string) *Statement {
+	return p.add(EffectAllow, sid, principal, resource, actions)
+}
+
+// Deny is like Allow but adds a statement that denies the actions.
+func (p *Policy) Deny(sid string, principal *Principal, resource string, actions ...string) *Statement {
+	return p.add(EffectDeny, sid, principal, resource, actions)
+}
+
+func (p *Policy) add(effect, sid string, principal *Principal, resource string, actions []string) *Statement {
+	s := &Statement{Sid: sid, Effect: effect, Principal: principal, Action: actions}
+	if resource != "" {
+		s.Resource = StringList{resource}
+	}
+	p.Statement = append(p.Statement, s)
+	return s
+}
+
+// Remove deletes the statements with the given sid from p and reports
+// whether there was any.
+func (p *Policy) Remove(sid string) bool {
+	kept := p.Statement[:0]
+	for _, s := range p.Statement {
+		if s.Sid != sid {
+			kept = append(kept, s)
+		}
+	}
+	removed := len(kept) != len(p.Statement)
+	p.Statement = kept
+	return removed
+}
+
+// Lookup returns the statement with the given sid, or nil.
+func (p *Policy) Lookup(sid string) *Statement {
+	for _, s := range p.Statement {
+		if s.Sid == sid {
+			return s
+		}
+	}
+	return nil
+}
+
+// When adds a condition to s, e.g. When("ArnEquals", ConditionSourceArn, topicArn),
+// and returns s.
+func (s *Statement) When(operator, key string, values ...string) *Statement {
+	if s.Condition == nil {
+		s.Condition = Conditions{}
+	}
+	if s.Condition[operator] == nil {
+		s.Condition[operator] = map[string]ConditionValues{}
+	}
+	for _, value := range values {
+		s.Condition[operator][key] = append(s.Condition[operator][key], value)
+	}
+	return s
+}
+
+// WhenSourceArn restricts s to requests made on behalf of the resource arn,
+// such as an SNS topic, and returns s.
+func (s *Statement) WhenSourceArn(arn string) *Statement {
+	return s.When("ArnEquals", ConditionSourceArn, arn)
+}
+
+// ParsePolicy decodes the value of a Policy queue attribute. A Statement
+// written as a single object rather than a list is accepted too.
+func ParsePolicy(value string) (*Policy, error) {
+	p := &Policy{}
+	if err := json.Unmarshal([]byte(value), p); err != nil {
+		return nil, fmt.Errorf("sqs: invalid queue policy: %v", err)
+	}
+	return p, nil
+}
+
+// Attribute returns p encoded as a Policy queue attribute, ready to be
+// passed to CreateQueue or SetQueueAttributes.
+func (p *Policy) Attribute() (Attribute, error) {
+	if err := p.validate(); err != nil {
+		return Attribute{}, err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return Attribute{}, err
+	}
+	return Attribute{"Policy", string(data)}, nil
+}
+
+func (p *Policy) validate() error {
+	if len(p.Statement) == 0 {
+		return fmt.Errorf("sqs: queue policy needs at least one statement")
+	}
+	for i, s := range p.Statement {
+		if s.Effect != EffectAllow && s.Effect != EffectDeny {
+			return fmt.Errorf("sqs: statement %d of queue policy has invalid effect %q", i+1, s.Effect)
+		}
+		if s.Principal == nil && s.NotPrincipal == nil {
+			return fmt.Errorf("sqs: statement %d of queue policy has no principal", i+1)
+		}
+		if len(s.Action) == 0 && len(s.NotAction) == 0 {
+			return fmt.Errorf("sqs: statement %d of queue policy has no action", i+1)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts a Statement written as a single object.
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Version   string          `json:"Version"`
+		Id        string          `json:"Id"`
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Policy{Version: raw.Version, Id: raw.Id}
+	if trimmed := bytes.TrimSpace(raw.Statement); len(trimmed) > 0 && trimmed[0] == '{' {
+		one := &Statement{}
+		if err := json.Unmarshal(trimmed, one); err != nil {
+			return err
+		}
+		p.Statement = []*Statement{one}
+		return nil
+	}
+	if len(raw.Statement) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw.Statement, &p.Statement)
+}
+
+// MarshalJSON writes a single element list as a plain string.
+func (l StringList) MarshalJSON() ([]byte, error) {
+	if len(l) == 1 {
+		return json.Marshal(l[0])
+	}
+	return json.Marshal([]string(l))
+}
+
+// UnmarshalJSON accepts both a plain string and a list of strings.
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*l = StringList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*l = many
+	return nil
+}
+
+// MarshalJSON writes a single value as a plain scalar.
+func (v ConditionValues) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]interface{}(v))
+}
+
+// UnmarshalJSON accepts both a plain scalar and a list of scalars.
+func (v *ConditionValues) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case []interface{}:
+		for _, element := range value {
+			if err := checkConditionValue(element); err != nil {
+				return err
+			}
+		}
+		*v = value
+	default:
+		if err := checkConditionValue(value); err != nil {
+			return err
+		}
+		*v = ConditionValues{value}
+	}
+	return nil
+}
+
+func checkConditionValue(value interface{}) error {
+	switch value.(type) {
+	case string, bool, json.Number:
+		return nil
+	}
+	return fmt.Errorf("invalid condition value %v", value)
+}
+
+// MarshalJSON writes "*" for anyone, and an object keyed by principal type
+// otherwise.
+func (p *Principal) MarshalJSON() ([]byte, error) {
+	if p.Anyone {
+		return json.Marshal("*")
+	}
+	types := make(map[string]StringList, len(p.Other)+4)
+	for kind, values := range p.Other {
+		types[kind] = values
+	}
+	for kind, values := range map[string]StringList{
+		"AWS":           p.AWS,
+		"Service":       p.Service,
+		"CanonicalUser": p.CanonicalUser,
+		"Federated":     p.Federated,
+	} {
+		if len(values) > 0 {
+			types[kind] = values
+		}
+	}
+	return json.Marshal(types)
+}
+
+// UnmarshalJSON accepts both "*" and an object keyed by principal type.
+func (p *Principal) UnmarshalJSON(data []byte) error {
+	var anyone string
+	if err := json.Unmarshal(data, &anyone); err == nil {
+		if anyone != "*" {
+			return fmt.Errorf("invalid principal %q", anyone)
+		}
+		*p = Principal{Anyone: true}
+		return nil
+	}
+	var raw map[string]StringList
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Principal{}
+	for kind, values := range raw {
+		switch kind {
+		case "AWS":
+			p.AWS = values
+		case "Service":
+			p.Service = values
+		case "CanonicalUser":
+			p.CanonicalUser = values
+		case "Federated":
+			p.Federated = values
+		default:
+			if p.Other == nil {
+				p.Other = map[string]StringList{}
+			}
+			p.Other[kind] = values
+		}
+	}
+	return nil
+}
+
+// PolicyDiff lists the statements that differ between two policies.
+// Statements are matched by Sid, or by content when they have none.
+type PolicyDiff struct {
+	Added   []*Statement
+	Removed []*Statement
+	Changed []StatementChange
+}
+
+// StatementChange is a statement whose Sid is in both policies with
+// different contents.
+type StatementChange struct {
+	Old *Statement
+	New *Statement
+}
+
+// Empty reports whether both policies grant the same permissions.
+func (d *PolicyDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffPolicies returns the changes that turn from into to. Either policy may
+// be nil, standing for a queue without a policy.
+func DiffPolicies(from, to *Policy) *PolicyDiff {
+	d := &PolicyDiff{}
+	var oldStatements, newStatements []*Statement
+	if from != nil {
+		oldStatements = from.Statement
+	}
+	if to != nil {
+		newStatements = to.Statement
+	}
+	matched := make([]bool, len(newStatements))
+	for _, o := range oldStatements {
+		found := false
+		for i, n := range newStatements {
+			if matched[i] {
+				continue
+			}
+			if o.Sid != "" && o.Sid == n.Sid {
+				if !reflect.DeepEqual(o, n) {
+					d.Changed = append(d.Changed, StatementChange{o, n})
+				}
+			} else if o.Sid != "" || n.Sid != "" || !reflect.DeepEqual(o, n) {
+				continue
+			}
+			matched[i], found = true, true
+			break
+		}
+		if !found {
+			d.Removed = append(d.Removed, o)
+		}
+	}
+	for i, n := range newStatements {
+		if !matched[i] {
+			d.Added = append(d.Added, n)
+		}
+	}
+	return d
+}
+
+// Policy is a helper function for GetQueueAttributes action that returns the access policy of
+// the queue, or nil if it has none.
+func (q *Queue) Policy() (*Policy, error) {
+	return q.PolicyContext(context.Background())
+}
+
+// PolicyContext is like Policy but carries ctx into the request.
+func (q *Queue) PolicyContext(ctx context.Context) (*Policy, error) {
+	value, err := q.attribute(ctx, "Policy")
+	if err != nil || value == "" {
+		return nil, err
+	}
+	return ParsePolicy(value)
+}
+
+// SetPolicy is a helper function for SetQueueAttributes action that replaces the access policy
+// of the queue.
+func (q *Queue) SetPolicy(policy *Policy) (resp *SetQueueAttributesResponse, err error) {
+	return q.SetPolicyContext(context.Background(), policy)
+}
+
+// SetPolicyContext is like SetPolicy but carries ctx into the request.
+func (q *Queue) SetPolicyContext(ctx context.Context, policy *Policy) (resp *SetQueueAttributesResponse, err error) {
+	attribute, err := policy.Attribute()
+	if err != nil {
+		return nil, err
+	}
+	return q.SetQueueAttributesContext(ctx, attribute)
+}
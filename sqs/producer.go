@@ -0,0 +1,217 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxBatchBytes is the largest total payload of a SendMessageBatch request
+// the Producer builds by default.
+const MaxBatchBytes = 256 * 1024
+
+// ErrProducerClosed is returned by Producer.Send once Close was called.
+var ErrProducerClosed = fmt.Errorf("sqs: producer is closed")
+
+// Producer coalesces messages sent from many goroutines into
+// SendMessageBatch requests. A batch goes out when it holds 10 messages,
+// when one more message would push it over MaxBatchBytes, or Linger after
+// its first message came in, whichever happens first. Configure it through
+// its fields before the first Send.
+type Producer struct {
+	Queue *Queue
+
+	// Linger is how long a message may wait for others to join its batch,
+	// 10 milliseconds by default.
+	Linger time.Duration
+
+	// MaxBatchBytes bounds the payload of a batch, MaxBatchBytes by default.
+	MaxBatchBytes int
+
+	mu      sync.Mutex
+	batch   []*pendingMessage
+	bytes   int
+	timer   *time.Timer
+	gen     int // Incremented with every batch, so stale timers can tell
+	closed  bool
+	sending sync.WaitGroup
+	last    chan struct{} // Closed once the last batch flushed is sent
+}
+
+type pendingMessage struct {
+	entry  SendMessageBatchRequestEntry
+	size   int
+	result chan sendResult
+}
+
+type sendResult struct {
+	entry *SendMessageBatchResultEntry
+	err   error
+}
+
+// NewProducer returns a producer sending to q with default settings.
+func NewProducer(q *Queue) *Producer {
+	return &Producer{Queue: q, Linger: 10 * time.Millisecond, MaxBatchBytes: MaxBatchBytes}
+}
+
+// Send queues a message for the next batch and waits for the outcome of
+// that batch for this message. It returns the result entry SQS sent back,
+// which holds the MessageId, or the error the message alone or its whole
+// batch failed with. A *ChecksumError comes with the result entry, as SQS
+// did accept the message. When ctx is done first Send returns ctx.Err(), but the
+// message may still be sent.
+func (p *Producer) Send(ctx context.Context, params SendMessageParams) (*SendMessageBatchResultEntry, error) {
+	if err := p.Queue.validateFifoMessage(params.MessageGroupId, params.DelaySeconds); err != nil {
+		return nil, err
+	}
+	m := &pendingMessage{
+		entry: SendMessageBatchRequestEntry{
+			MessageBody:            params.MessageBody,
+			DelaySeconds:           params.DelaySeconds,
+			MessageAttributes:      params.MessageAttributes,
+			MessageGroupId:         params.MessageGroupId,
+			MessageDeduplicationId: params.MessageDeduplicationId,
+		},
+		size:   messageSize(params.MessageBody, params.MessageAttributes),
+		result: make(chan sendResult, 1),
+	}
+	if err := p.add(m); err != nil {
+		return nil, err
+	}
+	select {
+	case r := <-m.result:
+		return r.entry, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// messageSize returns the size SQS counts for a message: its body and the
+// names, types and values of its attributes.
+func messageSize(body string, attrs map[string]MessageAttributeValue) int {
+	size := len(body)
+	for name, value := range attrs {
+		size += len(name) + len(value.DataType) + len(value.StringValue) + len(value.BinaryValue)
+	}
+	return size
+}
+
+func (p *Producer) add(m *pendingMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrProducerClosed
+	}
+	max := p.MaxBatchBytes
+	if max <= 0 {
+		max = MaxBatchBytes
+	}
+	if len(p.batch) > 0 && p.bytes+m.size > max {
+		p.flushLocked()
+	}
+	p.batch = append(p.batch, m)
+	p.bytes += m.size
+	if len(p.batch) == maxBatchEntries || p.bytes >= max {
+		p.flushLocked()
+	} else if len(p.batch) == 1 {
+		gen := p.gen
+		p.timer = time.AfterFunc(p.Linger, func() { p.lingered(gen) })
+	}
+	return nil
+}
+
+// lingered flushes the batch the timer was started for, unless it is gone.
+func (p *Producer) lingered(gen int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if gen == p.gen && len(p.batch) > 0 {
+		p.flushLocked()
+	}
+}
+
+// Flush sends the messages buffered so far without waiting for the batch
+// to fill up.
+func (p *Producer) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.batch) > 0 {
+		p.flushLocked()
+	}
+}
+
+// Close flushes the buffered messages, waits until every batch is sent and
+// makes further calls to Send fail with ErrProducerClosed.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	if len(p.batch) > 0 {
+		p.flushLocked()
+	}
+	p.mu.Unlock()
+	p.sending.Wait()
+	return nil
+}
+
+// flushLocked hands the current batch over to a sender goroutine. p.mu
+// must be held. On a FIFO queue each sender waits for the previous batch to
+// be sent first, so that batches go out in the order they were flushed.
+func (p *Producer) flushLocked() {
+	batch := p.batch
+	p.batch, p.bytes = nil, 0
+	p.gen++
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	previous, sent := p.last, make(chan struct{})
+	p.last = sent
+	p.sending.Add(1)
+	go func() {
+		defer p.sending.Done()
+		defer close(sent)
+		if previous != nil && p.Queue.IsFifo() {
+			<-previous
+		}
+		p.send(batch)
+	}()
+}
+
+func (p *Producer) send(batch []*pendingMessage) {
+	entries := make([]SendMessageBatchRequestEntry, len(batch))
+	for i, m := range batch {
+		entries[i] = m.entry
+		entries[i].Id = strconv.Itoa(i)
+	}
+	resp, err := p.Queue.SendMessageBatchContext(context.Background(), entries)
+	var mismatched map[string]*ChecksumError
+	if checksumErr, ok := err.(*BatchChecksumError); ok {
+		// SQS took the batch, only the mismatched entries are in doubt.
+		mismatched = checksumErr.Entries
+	} else if err != nil {
+		for _, m := range batch {
+			m.result <- sendResult{err: err}
+		}
+		return
+	}
+
+	results := make(map[string]sendResult, len(batch))
+	for i := range resp.Entries {
+		r := sendResult{entry: &resp.Entries[i]}
+		if checkErr, ok := mismatched[resp.Entries[i].Id]; ok {
+			r.err = checkErr
+		}
+		results[resp.Entries[i].Id] = r
+	}
+	for _, failed := range resp.Failed {
+		results[failed.Id] = sendResult{err: failed}
+	}
+	for i, m := range batch {
+		r, ok := results[entries[i].Id]
+		if !ok {
+			r.err = fmt.Errorf("sqs: SendMessageBatch response does not account for message %s", entries[i].Id)
+		}
+		m.result <- r
+	}
+}
@@ -0,0 +1,356 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// QueueAttributes is the typed form of the attributes of a queue.
+//
+// When the attributes are sent to SQS, by CreateQueueWithAttributes or
+// SetAttributes, fields holding their zero value are left out, and so are
+// the read-only fields that only GetQueueAttributes reports. FifoQueue is
+// only sent by CreateQueueWithAttributes, as it cannot be changed once the
+// queue exists. Use SetQueueAttributes with a plain Attribute to reset a
+// setting to zero.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_GetQueueAttributes.html for more details
+type QueueAttributes struct {
+	DelaySeconds           time.Duration // Whole seconds, up to 15 minutes
+	MaximumMessageSize     int64         // In bytes
+	MessageRetentionPeriod time.Duration // Whole seconds, from 1 minute to 14 days
+	ReceiveMessageWaitTime time.Duration // Whole seconds, up to 20 seconds
+	VisibilityTimeout      time.Duration // Whole seconds, up to 12 hours
+	Policy                 *Policy
+	RedrivePolicy          *RedrivePolicy
+	RedriveAllowPolicy     *RedriveAllowPolicy
+
+	// Server-side encryption, see Encryption.
+	KmsMasterKeyId        string
+	KmsDataKeyReusePeriod time.Duration // Whole seconds, from 1 minute to 24 hours
+	SqsManagedSseEnabled  bool
+
+	// FIFO queues only.
+	FifoQueue                 bool
+	ContentBasedDeduplication bool
+	DeduplicationScope        string
+	FifoThroughputLimit       string
+
+	// Read-only.
+	QueueArn                              string
+	ApproximateNumberOfMessages           int64
+	ApproximateNumberOfMessagesNotVisible int64
+	ApproximateNumberOfMessagesDelayed    int64
+	CreatedTimestamp                      time.Time
+	LastModifiedTimestamp                 time.Time
+}
+
+// ParseQueueAttributes decodes attributes as returned by GetQueueAttributes.
+// Attributes it does not know about are ignored.
+func ParseQueueAttributes(attributes []Attribute) (*QueueAttributes, error) {
+	a := &QueueAttributes{}
+	for _, attribute := range attributes {
+		if err := a.parse(attribute.Name, attribute.Value); err != nil {
+			return nil, fmt.Errorf("sqs: invalid value %q for queue attribute %s: %v", attribute.Value, attribute.Name, err)
+		}
+	}
+	return a, nil
+}
+
+func (a *QueueAttributes) parse(name, value string) (err error) {
+	switch name {
+	case "DelaySeconds":
+		a.DelaySeconds, err = parseSeconds(value)
+	case "MaximumMessageSize":
+		a.MaximumMessageSize, err = strconv.ParseInt(value, 10, 64)
+	case "MessageRetentionPeriod":
+		a.MessageRetentionPeriod, err = parseSeconds(value)
+	case "ReceiveMessageWaitTimeSeconds":
+		a.ReceiveMessageWaitTime, err = parseSeconds(value)
+	case "VisibilityTimeout":
+		a.VisibilityTimeout, err = parseSeconds(value)
+	case "Policy":
+		if value != "" {
+			a.Policy, err = ParsePolicy(value)
+		}
+	case "RedrivePolicy":
+		if value != "" {
+			a.RedrivePolicy, err = ParseRedrivePolicy(value)
+		}
+	case "RedriveAllowPolicy":
+		if value != "" {
+			a.RedriveAllowPolicy, err = ParseRedriveAllowPolicy(value)
+		}
+	case "KmsMasterKeyId":
+		a.KmsMasterKeyId = value
+	case "KmsDataKeyReusePeriodSeconds":
+		a.KmsDataKeyReusePeriod, err = parseSeconds(value)
+	case "SqsManagedSseEnabled":
+		a.SqsManagedSseEnabled, err = strconv.ParseBool(value)
+	case "FifoQueue":
+		a.FifoQueue, err = strconv.ParseBool(value)
+	case "ContentBasedDeduplication":
+		a.ContentBasedDeduplication, err = strconv.ParseBool(value)
+	case "DeduplicationScope":
+		a.DeduplicationScope = value
+	case "FifoThroughputLimit":
+		a.FifoThroughputLimit = value
+	case "QueueArn":
+		a.QueueArn = value
+	case "ApproximateNumberOfMessages":
+		a.ApproximateNumberOfMessages, err = strconv.ParseInt(value, 10, 64)
+	case "ApproximateNumberOfMessagesNotVisible":
+		a.ApproximateNumberOfMessagesNotVisible, err = strconv.ParseInt(value, 10, 64)
+	case "ApproximateNumberOfMessagesDelayed":
+		a.ApproximateNumberOfMessagesDelayed, err = strconv.ParseInt(value, 10, 64)
+	case "CreatedTimestamp":
+		a.CreatedTimestamp, err = parseEpochSeconds(value)
+	case "LastModifiedTimestamp":
+		a.LastModifiedTimestamp, err = parseEpochSeconds(value)
+	}
+	return
+}
+
+// Attributes returns the settable, non-zero fields of a in wire form, as
+// taken by SetQueueAttributes. FifoQueue is not among them.
+func (a *QueueAttributes) Attributes() ([]Attribute, error) {
+	var attributes []Attribute
+	add := func(name, value string) {
+		attributes = append(attributes, Attribute{name, value})
+	}
+	if a.DelaySeconds != 0 {
+		add("DelaySeconds", formatSeconds(a.DelaySeconds))
+	}
+	if a.MaximumMessageSize != 0 {
+		add("MaximumMessageSize", strconv.FormatInt(a.MaximumMessageSize, 10))
+	}
+	if a.MessageRetentionPeriod != 0 {
+		add("MessageRetentionPeriod", formatSeconds(a.MessageRetentionPeriod))
+	}
+	if a.ReceiveMessageWaitTime != 0 {
+		add("ReceiveMessageWaitTimeSeconds", formatSeconds(a.ReceiveMessageWaitTime))
+	}
+	if a.VisibilityTimeout != 0 {
+		add("VisibilityTimeout", formatSeconds(a.VisibilityTimeout))
+	}
+	if a.Policy != nil {
+		attribute, err := a.Policy.Attribute()
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, attribute)
+	}
+	if a.RedrivePolicy != nil {
+		attribute, err := a.RedrivePolicy.Attribute()
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, attribute)
+	}
+	if a.RedriveAllowPolicy != nil {
+		attribute, err := a.RedriveAllowPolicy.Attribute()
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, attribute)
+	}
+	if a.KmsMasterKeyId != "" {
+		add("KmsMasterKeyId", a.KmsMasterKeyId)
+	}
+	if a.KmsDataKeyReusePeriod != 0 {
+		add("KmsDataKeyReusePeriodSeconds", formatSeconds(a.KmsDataKeyReusePeriod))
+	}
+	if a.SqsManagedSseEnabled {
+		add("SqsManagedSseEnabled", "true")
+	}
+	if a.ContentBasedDeduplication {
+		add("ContentBasedDeduplication", "true")
+	}
+	if a.DeduplicationScope != "" {
+		add("DeduplicationScope", a.DeduplicationScope)
+	}
+	if a.FifoThroughputLimit != "" {
+		add("FifoThroughputLimit", a.FifoThroughputLimit)
+	}
+	return attributes, nil
+}
+
+func parseSeconds(value string) (time.Duration, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	return time.Duration(n) * time.Second, err
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+func parseEpochSeconds(value string) (time.Time, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(n, 0), nil
+}
+
+// QueueAttributes returns the attributes of the response in typed form.
+func (r *GetQueueAttributesResponse) QueueAttributes() (*QueueAttributes, error) {
+	return ParseQueueAttributes(r.Attributes)
+}
+
+// CreateQueueWithAttributes is like CreateQueueWithTags but takes the attributes of the new
+// queue in typed form.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_CreateQueue.html for more details
+func (s *SQS) CreateQueueWithAttributes(name string, attributes *QueueAttributes, tags map[string]string) (queue *Queue, err error) {
+	return s.CreateQueueWithAttributesContext(context.Background(), name, attributes, tags)
+}
+
+// CreateQueueWithAttributesContext is like CreateQueueWithAttributes but carries ctx into the request.
+func (s *SQS) CreateQueueWithAttributesContext(ctx context.Context, name string, attributes *QueueAttributes, tags map[string]string) (queue *Queue, err error) {
+	var attrs []Attribute
+	if attributes != nil {
+		if attributes.FifoQueue {
+			attrs = append(attrs, Attribute{"FifoQueue", "true"})
+		}
+		settable, err := attributes.Attributes()
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, settable...)
+	}
+	return s.CreateQueueWithTagsContext(ctx, name, attrs, tags)
+}
+
+// Attributes is a helper function for GetQueueAttributes action that returns all the attributes
+// of the queue in typed form.
+func (q *Queue) Attributes() (*QueueAttributes, error) {
+	return q.AttributesContext(context.Background())
+}
+
+// AttributesContext is like Attributes but carries ctx into the request.
+func (q *Queue) AttributesContext(ctx context.Context) (*QueueAttributes, error) {
+	resp, err := q.GetQueueAttributesContext(ctx, []string{"All"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.QueueAttributes()
+}
+
+// SetAttributes is a helper function for SetQueueAttributes action that sets the settable,
+// non-zero fields of attributes on the queue in a single request.
+func (q *Queue) SetAttributes(attributes *QueueAttributes) (resp *SetQueueAttributesResponse, err error) {
+	return q.SetAttributesContext(context.Background(), attributes)
+}
+
+// SetAttributesContext is like SetAttributes but carries ctx into the requests.
+func (q *Queue) SetAttributesContext(ctx context.Context, attributes *QueueAttributes) (resp *SetQueueAttributesResponse, err error) {
+	attrs, err := attributes.Attributes()
+	if err != nil {
+		return nil, err
+	}
+	return q.SetQueueAttributesContext(ctx, attrs...)
+}
+
+// attributeRanges holds the valid range of the numeric queue attributes.
+var attributeRanges = map[string][2]int64{
+	"DelaySeconds":                  {0, 900},
+	"MaximumMessageSize":            {1024, 1048576},
+	"MessageRetentionPeriod":        {60, 1209600},
+	"ReceiveMessageWaitTimeSeconds": {0, 20},
+	"VisibilityTimeout":             {0, 43200},
+	"KmsDataKeyReusePeriodSeconds":  {60, 86400},
+}
+
+// attributeChoices holds the valid values of the enumerated queue attributes.
+var attributeChoices = map[string][]string{
+	"SqsManagedSseEnabled":      {"true", "false"},
+	"FifoQueue":                 {"true", "false"},
+	"ContentBasedDeduplication": {"true", "false"},
+	"DeduplicationScope":        {"messageGroup", "queue"},
+	"FifoThroughputLimit":       {"perQueue", "perMessageGroupId"},
+}
+
+// validateQueueAttributes checks that attributes only holds settable
+// attributes, each given once, with a value SQS accepts.
+func validateQueueAttributes(attributes []Attribute) error {
+	values := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		if _, ok := values[attribute.Name]; ok {
+			return fmt.Errorf("sqs: queue attribute %s is given more than once", attribute.Name)
+		}
+		values[attribute.Name] = attribute.Value
+		if err := validateQueueAttribute(attribute); err != nil {
+			return err
+		}
+	}
+	if values["KmsMasterKeyId"] != "" && values["SqsManagedSseEnabled"] == "true" {
+		return fmt.Errorf("sqs: queue attributes KmsMasterKeyId and SqsManagedSseEnabled cannot be used together")
+	}
+	return nil
+}
+
+func validateQueueAttribute(attribute Attribute) error {
+	name, value := attribute.Name, attribute.Value
+	if r, ok := attributeRanges[name]; ok {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < r[0] || n > r[1] {
+			return fmt.Errorf("sqs: queue attribute %s must be an integer from %d to %d, not %q", name, r[0], r[1], value)
+		}
+		return nil
+	}
+	if choices, ok := attributeChoices[name]; ok {
+		for _, choice := range choices {
+			if value == choice {
+				return nil
+			}
+		}
+		return fmt.Errorf("sqs: queue attribute %s must be one of %v, not %q", name, choices, value)
+	}
+	switch name {
+	case "KmsMasterKeyId":
+		return nil
+	case "Policy":
+		// Left to SQS beyond syntax, so that any policy it accepts can be set.
+		var policy interface{}
+		if value != "" {
+			if err := json.Unmarshal([]byte(value), &policy); err != nil {
+				return fmt.Errorf("sqs: invalid queue policy: %v", err)
+			}
+		}
+		return nil
+	case "RedrivePolicy":
+		if value == "" {
+			return nil
+		}
+		p, err := ParseRedrivePolicy(value)
+		if err == nil {
+			_, err = p.Attribute()
+		}
+		return err
+	case "RedriveAllowPolicy":
+		if value == "" {
+			return nil
+		}
+		p, err := ParseRedriveAllowPolicy(value)
+		if err == nil {
+			_, err = p.Attribute()
+		}
+		return err
+	case "QueueArn", "ApproximateNumberOfMessages", "ApproximateNumberOfMessagesNotVisible",
+		"ApproximateNumberOfMessagesDelayed", "CreatedTimestamp", "LastModifiedTimestamp":
+		return fmt.Errorf("sqs: queue attribute %s is read-only", name)
+	}
+	return fmt.Errorf("sqs: unknown queue attribute %q", name)
+}
+
+// addAttributeParams encodes attributes into params as Attribute.N.Name and
+// Attribute.N.Value.
+func addAttributeParams(params map[string]string, attributes []Attribute) {
+	for i, attribute := range attributes {
+		params["Attribute."+strconv.Itoa(i+1)+".Name"] = attribute.Name
+		params["Attribute."+strconv.Itoa(i+1)+".Value"] = attribute.Value
+	}
+}
@@ -0,0 +1,223 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Permissions a RedriveAllowPolicy can grant.
+const (
+	RedrivePermissionAllowAll = "allowAll"
+	RedrivePermissionDenyAll  = "denyAll"
+	RedrivePermissionByQueue  = "byQueue"
+)
+
+// RedrivePolicy sends messages that were received MaxReceiveCount times
+// without being deleted to the dead-letter queue DeadLetterTargetArn.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-dead-letter-queues.html for more details
+type RedrivePolicy struct {
+	DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	MaxReceiveCount     int    `json:"maxReceiveCount"`
+}
+
+// RedriveAllowPolicy restricts which source queues may use a queue as their
+// dead-letter queue. SourceQueueArns is only used with RedrivePermissionByQueue.
+type RedriveAllowPolicy struct {
+	RedrivePermission string   `json:"redrivePermission"`
+	SourceQueueArns   []string `json:"sourceQueueArns,omitempty"`
+}
+
+// UnmarshalJSON accepts maxReceiveCount both as a number and as a string,
+// since SQS has returned it either way.
+func (p *RedrivePolicy) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		DeadLetterTargetArn string          `json:"deadLetterTargetArn"`
+		MaxReceiveCount     json.RawMessage `json:"maxReceiveCount"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.DeadLetterTargetArn = raw.DeadLetterTargetArn
+	p.MaxReceiveCount = 0
+	if len(raw.MaxReceiveCount) == 0 {
+		return nil
+	}
+	var count string
+	if err := json.Unmarshal(raw.MaxReceiveCount, &count); err != nil {
+		count = string(raw.MaxReceiveCount)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return fmt.Errorf("sqs: invalid maxReceiveCount %s in redrive policy", raw.MaxReceiveCount)
+	}
+	p.MaxReceiveCount = n
+	return nil
+}
+
+// ParseRedrivePolicy decodes the value of a RedrivePolicy queue attribute.
+func ParseRedrivePolicy(value string) (*RedrivePolicy, error) {
+	p := &RedrivePolicy{}
+	if err := json.Unmarshal([]byte(value), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Attribute returns p encoded as a RedrivePolicy queue attribute, ready to
+// be passed to CreateQueue or SetQueueAttributes.
+func (p *RedrivePolicy) Attribute() (Attribute, error) {
+	if p.DeadLetterTargetArn == "" || p.MaxReceiveCount < 1 {
+		return Attribute{}, fmt.Errorf("sqs: redrive policy needs a dead-letter target and a positive maxReceiveCount")
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return Attribute{}, err
+	}
+	return Attribute{"RedrivePolicy", string(data)}, nil
+}
+
+// ParseRedriveAllowPolicy decodes the value of a RedriveAllowPolicy queue attribute.
+func ParseRedriveAllowPolicy(value string) (*RedriveAllowPolicy, error) {
+	p := &RedriveAllowPolicy{}
+	if err := json.Unmarshal([]byte(value), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Attribute returns p encoded as a RedriveAllowPolicy queue attribute, ready
+// to be passed to CreateQueue or SetQueueAttributes.
+func (p *RedriveAllowPolicy) Attribute() (Attribute, error) {
+	switch p.RedrivePermission {
+	case RedrivePermissionAllowAll, RedrivePermissionDenyAll:
+		if len(p.SourceQueueArns) > 0 {
+			return Attribute{}, fmt.Errorf("sqs: sourceQueueArns can only be given with redrivePermission %s", RedrivePermissionByQueue)
+		}
+	case RedrivePermissionByQueue:
+		if len(p.SourceQueueArns) == 0 || len(p.SourceQueueArns) > 10 {
+			return Attribute{}, fmt.Errorf("sqs: redrivePermission %s needs between 1 and 10 sourceQueueArns", RedrivePermissionByQueue)
+		}
+	default:
+		return Attribute{}, fmt.Errorf("sqs: invalid redrivePermission %q", p.RedrivePermission)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return Attribute{}, err
+	}
+	return Attribute{"RedriveAllowPolicy", string(data)}, nil
+}
+
+// attribute returns the value of a single attribute of q, or an empty string
+// when the queue does not have it.
+func (q *Queue) attribute(ctx context.Context, name string) (string, error) {
+	resp, err := q.GetQueueAttributesContext(ctx, []string{name})
+	if err != nil {
+		return "", err
+	}
+	for _, attribute := range resp.Attributes {
+		if attribute.Name == name {
+			return attribute.Value, nil
+		}
+	}
+	return "", nil
+}
+
+// Arn is a helper function for GetQueueAttributes action that returns the Amazon Resource Name of
+// the queue, which redrive policies use to refer to it.
+func (q *Queue) Arn() (string, error) {
+	return q.ArnContext(context.Background())
+}
+
+// ArnContext is like Arn but carries ctx into the request.
+func (q *Queue) ArnContext(ctx context.Context) (string, error) {
+	return q.attribute(ctx, "QueueArn")
+}
+
+// RedrivePolicy is a helper function for GetQueueAttributes action that returns the redrive policy
+// of the queue, or nil if it has none.
+func (q *Queue) RedrivePolicy() (*RedrivePolicy, error) {
+	return q.RedrivePolicyContext(context.Background())
+}
+
+// RedrivePolicyContext is like RedrivePolicy but carries ctx into the request.
+func (q *Queue) RedrivePolicyContext(ctx context.Context) (*RedrivePolicy, error) {
+	value, err := q.attribute(ctx, "RedrivePolicy")
+	if err != nil || value == "" {
+		return nil, err
+	}
+	return ParseRedrivePolicy(value)
+}
+
+// SetRedrivePolicy is a helper function for SetQueueAttributes action that sets the redrive policy
+// of the queue.
+func (q *Queue) SetRedrivePolicy(policy *RedrivePolicy) (resp *SetQueueAttributesResponse, err error) {
+	return q.SetRedrivePolicyContext(context.Background(), policy)
+}
+
+// SetRedrivePolicyContext is like SetRedrivePolicy but carries ctx into the request.
+func (q *Queue) SetRedrivePolicyContext(ctx context.Context, policy *RedrivePolicy) (resp *SetQueueAttributesResponse, err error) {
+	attribute, err := policy.Attribute()
+	if err != nil {
+		return nil, err
+	}
+	return q.SetQueueAttributesContext(ctx, attribute)
+}
+
+// RedriveAllowPolicy is a helper function for GetQueueAttributes action that returns the redrive
+// allow policy of the queue, or nil if it has none.
+func (q *Queue) RedriveAllowPolicy() (*RedriveAllowPolicy, error) {
+	return q.RedriveAllowPolicyContext(context.Background())
+}
+
+// RedriveAllowPolicyContext is like RedriveAllowPolicy but carries ctx into the request.
+func (q *Queue) RedriveAllowPolicyContext(ctx context.Context) (*RedriveAllowPolicy, error) {
+	value, err := q.attribute(ctx, "RedriveAllowPolicy")
+	if err != nil || value == "" {
+		return nil, err
+	}
+	return ParseRedriveAllowPolicy(value)
+}
+
+// SetRedriveAllowPolicy is a helper function for SetQueueAttributes action that sets which source
+// queues may use the queue as their dead-letter queue.
+func (q *Queue) SetRedriveAllowPolicy(policy *RedriveAllowPolicy) (resp *SetQueueAttributesResponse, err error) {
+	return q.SetRedriveAllowPolicyContext(context.Background(), policy)
+}
+
+// SetRedriveAllowPolicyContext is like SetRedriveAllowPolicy but carries ctx into the request.
+func (q *Queue) SetRedriveAllowPolicyContext(ctx context.Context, policy *RedriveAllowPolicy) (resp *SetQueueAttributesResponse, err error) {
+	attribute, err := policy.Attribute()
+	if err != nil {
+		return nil, err
+	}
+	return q.SetQueueAttributesContext(ctx, attribute)
+}
+
+// DeadLetterSourceQueues is a helper function for ListDeadLetterSourceQueues action that follows
+// every page of results and returns handles to all the queues that use the queue as their
+// dead-letter queue.
+func (q *Queue) DeadLetterSourceQueues() ([]*Queue, error) {
+	return q.DeadLetterSourceQueuesContext(context.Background())
+}
+
+// DeadLetterSourceQueuesContext is like DeadLetterSourceQueues but carries ctx into the requests.
+func (q *Queue) DeadLetterSourceQueuesContext(ctx context.Context) ([]*Queue, error) {
+	var queues []*Queue
+	nextToken := ""
+	for {
+		resp, err := q.ListDeadLetterSourceQueuesPageContext(ctx, 1000, nextToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, url := range resp.QueueUrls {
+			queues = append(queues, &Queue{q.SQS, url})
+		}
+		if resp.NextToken == "" {
+			return queues, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
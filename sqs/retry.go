@@ -0,0 +1,43 @@
+package sqs
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy describes how requests that fail with a transient error are
+// retried. Delays grow exponentially from BaseDelay up to MaxDelay and are
+// drawn at random below that bound ("full jitter"), so that many clients
+// backing off at once do not retry in lockstep.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts per request; 1 or less disables retries
+	BaseDelay   time.Duration // Upper bound of the delay before the first retry
+	MaxDelay    time.Duration // Cap on the upper bound as it grows; zero means no cap
+}
+
+// DefaultRetryPolicy is the policy used by handles created with New.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// NoRetry disables retries when assigned to SQS.Retry.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// delay returns how long to wait after the given failed attempt, counting
+// from 1.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	ceil := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || ceil < p.MaxDelay) && ceil <= math.MaxInt64/2; i++ {
+		ceil *= 2
+	}
+	if p.MaxDelay > 0 && ceil > p.MaxDelay {
+		ceil = p.MaxDelay
+	}
+	if ceil <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceil)))
+}
@@ -4,9 +4,20 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"launchpad.net/goamz/aws"
+	"net/http"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+)
+
+// Signature versions understood by SQS.SignatureVersion.
+const (
+	SignatureV2 = 2
+	SignatureV4 = 4
 )
 
 // ----------------------------------------------------------------------------
@@ -14,7 +25,7 @@ import (
 
 var b64 = base64.StdEncoding
 
-func sign(auth aws.Auth, method, path string, params map[string]string, host string) {
+func signV2(auth aws.Auth, method, path string, params map[string]string, host string) {
 	params["AWSAccessKeyId"] = auth.AccessKey
 	params["SignatureVersion"] = "2"
 	params["SignatureMethod"] = "HmacSHA256"
@@ -33,3 +44,166 @@ func sign(auth aws.Auth, method, path string, params map[string]string, host str
 
 	params["Signature"] = string(signature)
 }
+
+// ----------------------------------------------------------------------------
+// Signature Version 4 signing (http://docs.aws.amazon.com/general/latest/gr/signature-version-4.html)
+
+const (
+	v4Algorithm  = "AWS4-HMAC-SHA256"
+	v4Service    = "sqs"
+	v4Terminator = "aws4_request"
+	v4TimeFormat = "20060102T150405Z"
+	v4DateFormat = "20060102"
+
+	// Lifetime of the signature carried in a presigned query string.
+	v4PresignExpiry = 15 * time.Minute
+)
+
+type v4Signer struct {
+	auth   aws.Auth
+	region string
+	t      time.Time
+}
+
+func newV4Signer(auth aws.Auth, region string, t time.Time) *v4Signer {
+	return &v4Signer{auth, region, t.UTC()}
+}
+
+// sign adds the X-Amz-Date and Authorization headers to req, whose body
+// must be payload.
+func (s *v4Signer) sign(req *http.Request, payload []byte) {
+	req.Header.Set("X-Amz-Date", s.t.Format(v4TimeFormat))
+
+	headers := map[string]string{"host": requestHost(req)}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	canonicalHeaders, signedHeaders := canonicalHeaders(headers)
+
+	creq := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		hashHex(payload),
+	}, "\n")
+
+	req.Header.Set("Authorization", v4Algorithm+" Credential="+s.auth.AccessKey+"/"+s.scope()+
+		", SignedHeaders="+signedHeaders+", Signature="+s.signature(creq))
+}
+
+// presign adds the signature and its parameters to the query string of u, so
+// that the URL can be requested with the given method and no further headers.
+func (s *v4Signer) presign(method string, u *url.URL) {
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", v4Algorithm)
+	query.Set("X-Amz-Credential", s.auth.AccessKey+"/"+s.scope())
+	query.Set("X-Amz-Date", s.t.Format(v4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(v4PresignExpiry/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalHeaders, signedHeaders := canonicalHeaders(map[string]string{"host": u.Host})
+	rawQuery := canonicalQuery(query)
+	creq := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		rawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		hashHex(nil),
+	}, "\n")
+
+	u.RawQuery = rawQuery + "&X-Amz-Signature=" + s.signature(creq)
+}
+
+func (s *v4Signer) scope() string {
+	return s.t.Format(v4DateFormat) + "/" + s.region + "/" + v4Service + "/" + v4Terminator
+}
+
+func (s *v4Signer) signature(canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		v4Algorithm,
+		s.t.Format(v4TimeFormat),
+		s.scope(),
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+	return hex.EncodeToString(hmacSHA256(s.signingKey(), stringToSign))
+}
+
+func (s *v4Signer) signingKey() []byte {
+	key := hmacSHA256([]byte("AWS4"+s.auth.SecretKey), s.t.Format(v4DateFormat))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, v4Service)
+	return hmacSHA256(key, v4Terminator)
+}
+
+func canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func canonicalQuery(values url.Values) string {
+	var sarray []string
+	for k, vs := range values {
+		for _, v := range vs {
+			sarray = append(sarray, aws.Encode(k)+"="+aws.Encode(v))
+		}
+	}
+	sort.Strings(sarray)
+	return strings.Join(sarray, "&")
+}
+
+// canonicalHeaders returns the canonical header block and the signed header
+// list for headers, whose names must already be lower case.
+func canonicalHeaders(headers map[string]string) (canonical, signed string) {
+	var names []string
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		canonical += k + ":" + headers[k] + "\n"
+	}
+	return canonical, strings.Join(names, ";")
+}
+
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+func hashHex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write([]byte(data))
+	return hash.Sum(nil)
+}
+
+// regionName returns the name of the region requests are signed for. When
+// the Region does not carry a name it is derived from the SQS endpoint,
+// which looks like sqs.<region>.amazonaws.com or <region>.queue.amazonaws.com.
+func (s *SQS) regionName() string {
+	if s.Region.Name != "" {
+		return s.Region.Name
+	}
+	if u, err := url.Parse(s.Region.SQSEndpoint); err == nil {
+		parts := strings.Split(u.Host, ".")
+		if len(parts) >= 4 && parts[0] == "sqs" {
+			return parts[1]
+		}
+		if len(parts) >= 4 && parts[1] == "queue" {
+			return parts[0]
+		}
+	}
+	return "us-east-1"
+}
@@ -10,6 +10,8 @@
 package sqs
 
 import (
+	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"launchpad.net/goamz/aws"
@@ -25,14 +27,49 @@ import (
 type SQS struct {
 	aws.Auth
 	aws.Region
+
+	// SignatureVersion selects how requests are signed. It defaults to
+	// SignatureV4; SignatureV2 remains available for legacy endpoints.
+	SignatureVersion int
+
+	// Method is the HTTP method requests are sent with. Parameters are
+	// form encoded into a POST body by default; set it to "GET" to send
+	// them in the query string instead.
+	Method string
+
+	// HTTPClient is used to send every request made through this handle
+	// and the queues obtained from it. When nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Retry controls how requests failing with a transient error are
+	// retried. New sets it to DefaultRetryPolicy.
+	Retry RetryPolicy
+
+	// DisableChecksums turns off the verification of the MD5 digests SQS
+	// returns for the bodies and attributes of sent and received messages,
+	// which otherwise fails with a *ChecksumError.
+	DisableChecksums bool
+
 	private byte // Reserve the right of using private data.
 }
 
 const debug = false
 
+// APIVersion is the version of the SQS query API spoken by this package.
+const APIVersion = "2012-11-05"
+
 // New creates a new SQS handle
 func New(auth aws.Auth, region aws.Region) *SQS {
-	return &SQS{auth, region, 0}
+	return &SQS{Auth: auth, Region: region, SignatureVersion: SignatureV4, Method: "POST", Retry: DefaultRetryPolicy}
+}
+
+// NewWithClient creates a new SQS handle that sends its requests through
+// client. Timeouts, proxies, TLS settings and a custom http.RoundTripper
+// can all be configured on the client.
+func NewWithClient(auth aws.Auth, region aws.Region, client *http.Client) *SQS {
+	s := New(auth, region)
+	s.HTTPClient = client
+	return s
 }
 
 // Queue type encapsulates operations on a SQS Queue
@@ -53,7 +90,8 @@ type CreateQueueResponse struct {
 //
 // See http://goo.gl/RPRWr for more details
 type ListQueuesResponse struct {
-	QueueUrl []string `xml:"ListQueuesResult>QueueUrl"`
+	QueueUrl  []string `xml:"ListQueuesResult>QueueUrl"`
+	NextToken string   `xml:"ListQueuesResult>NextToken"`
 	ResponseMetadata
 }
 
@@ -80,26 +118,35 @@ type ResponseMetadata struct {
 // Error represents an error in an operation with SQS
 type Error struct {
 	StatusCode int    // HTTP Status Code (200, 403, ... )
+	Type       string // Who is at fault: "Sender" or "Receiver"
 	Code       string // SQS Error Code
 	Message    string // The human-oriented error message
 	RequestId  string `xml:"RequestID"`
+
+	// Next is the following error when SQS returned more than one. Most
+	// callers only need the first, which is the Error itself.
+	Next *Error `xml:"-"`
 }
 
 func (err *Error) Error() string {
-	if err.Code != "" {
+	if err.Code == "" {
 		return err.Message
 	}
 
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
-// For now a single error inst is being exposed. In the future it may be useful
-// to provide access to all of them, but rather than doing it as an array/slice,
-// use a *next pointer, so that it's backward compatible and it continues to be
-// easy to handle the first error, which is what most people will want.
+// The first error returned is exposed directly and any further ones are
+// chained through Error.Next, so that it continues to be easy to handle the
+// first error, which is what most people will want.
+//
+// Errors come wrapped in an ErrorResponse element since API version
+// 2012-11-05; the older Response>Errors form is still understood.
 type xmlErrors struct {
-	RequestId string
-	Errors    []Error `xml:"Errors>Error"`
+	RequestId       string
+	LegacyRequestId string  `xml:"RequestID"`
+	Error           []Error `xml:"Error"`
+	Errors          []Error `xml:"Errors>Error"`
 }
 
 // Attribute represents an instance of a SQS Queue Attribute.
@@ -115,6 +162,34 @@ type ChangeMessageVisibilityBatchEntry struct {
 	VisibilityTimeout int
 }
 
+// MaxWaitTimeSeconds is the longest a ReceiveMessage call can wait for messages to arrive.
+const MaxWaitTimeSeconds = 20
+
+// ReceiveMessageParams holds the parameters of a ReceiveMessage request. Zero values are
+// left out of the request, so that the queue's defaults apply.
+//
+// See http://goo.gl/ThPrF for more details
+type ReceiveMessageParams struct {
+	// AttributeNames selects the system attributes to return with each
+	// message, see the Message accessors such as SentTimestamp.
+	AttributeNames []MessageSystemAttributeName
+	// MessageAttributeNames selects the message attributes to return; "All"
+	// returns every attribute and a name ending in ".*" matches a prefix.
+	MessageAttributeNames []string
+	MaxNumberOfMessages   int
+	VisibilityTimeout     int
+	// WaitTimeSeconds makes the call wait up to MaxWaitTimeSeconds for a message to
+	// arrive when the queue is empty, rather than returning immediately (long polling).
+	WaitTimeSeconds int
+	// ReceiveRequestAttemptId lets a FIFO queue recognise a retried call and return
+	// the same messages again.
+	ReceiveRequestAttemptId string
+
+	// sendZeros sends MaxNumberOfMessages and VisibilityTimeout even when
+	// zero, as ReceiveMessage always did.
+	sendZeros bool
+}
+
 // ReceiveMessageResponse holds the results of ReceiveMessage
 type ReceiveMessageResponse struct {
 	Messages []Message `xml:"ReceiveMessageResult>Message"`
@@ -138,36 +213,62 @@ type SendMessageBatchResponse struct {
 // SendMessageBatchResult holds the results of SendMessageBatch
 type SendMessageBatchResult struct {
 	Entries []SendMessageBatchResultEntry `xml:"SendMessageBatchResult>SendMessageBatchResultEntry"`
+	Failed  []BatchResultErrorEntry       `xml:"SendMessageBatchResult>BatchResultErrorEntry"`
 }
 
 type SendMessageBatchResultEntry struct {
-	MD5OfMessageBody string `xml:"MD5OfMessageBody"`
-	MessageId        string `xml:"MessageId"`
-	Id               string `xml:"Id"`
+	MD5OfMessageBody       string `xml:"MD5OfMessageBody"`
+	MD5OfMessageAttributes string `xml:"MD5OfMessageAttributes"`
+	MessageId              string `xml:"MessageId"`
+	Id                     string `xml:"Id"`
+	SequenceNumber         string `xml:"SequenceNumber"`
 }
 
 type SendMessageBatchRequestEntry struct {
-	Id           string
-	MessageBody  string
-	DelaySeconds int
+	Id                     string
+	MessageBody            string
+	DelaySeconds           int
+	MessageAttributes      map[string]MessageAttributeValue
+	MessageGroupId         string
+	MessageDeduplicationId string
+}
+
+// SendMessageParams holds the parameters of a SendMessage request.
+//
+// See http://goo.gl/7OnPb for more details
+type SendMessageParams struct {
+	MessageBody       string
+	DelaySeconds      int // Zero leaves the queue's DelaySeconds in effect
+	MessageAttributes map[string]MessageAttributeValue
+
+	// MessageGroupId is required on FIFO queues; messages of a group are
+	// delivered in order. MessageDeduplicationId may be left empty when
+	// the queue has ContentBasedDeduplication enabled.
+	MessageGroupId         string
+	MessageDeduplicationId string
 }
 
 type SendMessageResult struct {
-	MD5OfMessageBody string `xml:"SendMessageResult>MD5OfMessageBody"`
-	MessageId        string `xml:"SendMessageResult>MessageId"`
+	MD5OfMessageBody       string `xml:"SendMessageResult>MD5OfMessageBody"`
+	MD5OfMessageAttributes string `xml:"SendMessageResult>MD5OfMessageAttributes"`
+	MessageId              string `xml:"SendMessageResult>MessageId"`
+	SequenceNumber         string `xml:"SendMessageResult>SequenceNumber"` // Only set by FIFO queues
 }
 
 // Represents an instance of a SQS Message
 type Message struct {
-	MessageId     string      `xml:"MessageId"`
-	Body          string      `xml:"Body"`
-	MD5OfBody     string      `xml:"MD5OfBody"`
-	ReceiptHandle string      `xml:"ReceiptHandle"`
-	Attribute     []Attribute `xml:"Attribute"`
+	MessageId              string             `xml:"MessageId"`
+	Body                   string             `xml:"Body"`
+	MD5OfBody              string             `xml:"MD5OfBody"`
+	ReceiptHandle          string             `xml:"ReceiptHandle"`
+	Attribute              []Attribute        `xml:"Attribute"`
+	MessageAttribute       []MessageAttribute `xml:"MessageAttribute"`
+	MD5OfMessageAttributes string             `xml:"MD5OfMessageAttributes"`
 }
 
 type ChangeMessageVisibilityBatchResponse struct {
-	Id []string `xml:"ChangeMessageVisibilityBatchResult>ChangeMessageVisibilityBatchResultEntry>Id"`
+	Id     []string                `xml:"ChangeMessageVisibilityBatchResult>ChangeMessageVisibilityBatchResultEntry>Id"`
+	Failed []BatchResultErrorEntry `xml:"ChangeMessageVisibilityBatchResult>BatchResultErrorEntry"`
 	ResponseMetadata
 }
 
@@ -177,7 +278,8 @@ type GetQueueAttributesResponse struct {
 }
 
 type DeleteMessageBatchResult struct {
-	Ids []string `xml:"DeleteMessageBatchResult>DeleteMessageBatchResultEntry>Id"`
+	Ids    []string                `xml:"DeleteMessageBatchResult>DeleteMessageBatchResultEntry>Id"`
+	Failed []BatchResultErrorEntry `xml:"DeleteMessageBatchResult>BatchResultErrorEntry"`
 }
 
 type DeleteMessageBatchResponse struct {
@@ -211,21 +313,60 @@ type DeleteQueueResponse struct {
 	ResponseMetadata
 }
 
+// Response to a PurgeQueue request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_PurgeQueue.html for more details
+type PurgeQueueResponse struct {
+	ResponseMetadata
+}
+
+// Response to a ListDeadLetterSourceQueues request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListDeadLetterSourceQueues.html for more details
+type ListDeadLetterSourceQueuesResponse struct {
+	QueueUrls []string `xml:"ListDeadLetterSourceQueuesResult>QueueUrl"`
+	NextToken string   `xml:"ListDeadLetterSourceQueuesResult>NextToken"`
+	ResponseMetadata
+}
+
 // CreateQueue action creates a new queue.
 //
 // See http://goo.gl/sVUjF for more details
 func (s *SQS) CreateQueue(name string, attributes []Attribute) (queue *Queue, err error) {
+	return s.CreateQueueContext(context.Background(), name, attributes)
+}
+
+// CreateQueueContext is like CreateQueue but carries ctx into the request.
+func (s *SQS) CreateQueueContext(ctx context.Context, name string, attributes []Attribute) (queue *Queue, err error) {
+	return s.CreateQueueWithTagsContext(ctx, name, attributes, nil)
+}
+
+// CreateQueueWithTags is like CreateQueue but also tags the new queue.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_CreateQueue.html for more details
+func (s *SQS) CreateQueueWithTags(name string, attributes []Attribute, tags map[string]string) (queue *Queue, err error) {
+	return s.CreateQueueWithTagsContext(context.Background(), name, attributes, tags)
+}
+
+// CreateQueueWithTagsContext is like CreateQueueWithTags but carries ctx into the request.
+func (s *SQS) CreateQueueWithTagsContext(ctx context.Context, name string, attributes []Attribute, tags map[string]string) (queue *Queue, err error) {
 	resp := &CreateQueueResponse{}
 	params := makeParams("CreateQueue")
 	queue = nil
 
-	for i, attribute := range attributes {
-		params["AttributeName."+strconv.Itoa(i+1)+".Name"] = attribute.Name
-		params["AttributeName."+strconv.Itoa(i+1)+".Value"] = attribute.Value
+	if err = validateQueueName(name, attributes); err != nil {
+		return nil, err
+	}
+
+	if err = validateQueueAttributes(attributes); err != nil {
+		return nil, err
 	}
 
+	addAttributeParams(params, attributes)
+	addTagParams(params, tags)
+
 	params["QueueName"] = name
-	err = s.query("", params, resp)
+	err = s.query(ctx, "", params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -237,6 +378,11 @@ func (s *SQS) CreateQueue(name string, attributes []Attribute) (queue *Queue, er
 //
 // See http://goo.gl/8WBp8 for more details
 func (q *Queue) AddPermission(label string, accountPermissions []AccountPermission) (resp *AddPermissionResponse, err error) {
+	return q.AddPermissionContext(context.Background(), label, accountPermissions)
+}
+
+// AddPermissionContext is like AddPermission but carries ctx into the request.
+func (q *Queue) AddPermissionContext(ctx context.Context, label string, accountPermissions []AccountPermission) (resp *AddPermissionResponse, err error) {
 	resp = &AddPermissionResponse{}
 	params := makeParams("AddPermission")
 
@@ -246,7 +392,7 @@ func (q *Queue) AddPermission(label string, accountPermissions []AccountPermissi
 		params["ActionName."+strconv.Itoa(i+1)] = accountPermission.ActionName
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -254,11 +400,16 @@ func (q *Queue) AddPermission(label string, accountPermissions []AccountPermissi
 //
 // See http://goo.gl/YLOe8 for more details
 func (q *Queue) RemovePermission(label string) (resp *RemovePermissionResponse, err error) {
+	return q.RemovePermissionContext(context.Background(), label)
+}
+
+// RemovePermissionContext is like RemovePermission but carries ctx into the request.
+func (q *Queue) RemovePermissionContext(ctx context.Context, label string) (resp *RemovePermissionResponse, err error) {
 	resp = &RemovePermissionResponse{}
 	params := makeParams("RemovePermission")
 
 	params["Label"] = label
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -266,6 +417,11 @@ func (q *Queue) RemovePermission(label string) (resp *RemovePermissionResponse,
 //
 // See http://goo.gl/WejDu for more details
 func (q *Queue) GetQueueAttributes(attributes []string) (resp *GetQueueAttributesResponse, err error) {
+	return q.GetQueueAttributesContext(context.Background(), attributes)
+}
+
+// GetQueueAttributesContext is like GetQueueAttributes but carries ctx into the request.
+func (q *Queue) GetQueueAttributesContext(ctx context.Context, attributes []string) (resp *GetQueueAttributesResponse, err error) {
 	resp = &GetQueueAttributesResponse{}
 	params := makeParams("GetQueueAttributes")
 
@@ -273,7 +429,7 @@ func (q *Queue) GetQueueAttributes(attributes []string) (resp *GetQueueAttribute
 		params["AttributeName."+strconv.Itoa(i+1)] = attribute
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -281,13 +437,18 @@ func (q *Queue) GetQueueAttributes(attributes []string) (resp *GetQueueAttribute
 //
 // See http://goo.gl/EyJKF for more details
 func (q *Queue) ChangeMessageVisibility(receiptHandle string, visibilityTimeout int) (resp *ChangeMessageVisibilityResponse, err error) {
+	return q.ChangeMessageVisibilityContext(context.Background(), receiptHandle, visibilityTimeout)
+}
+
+// ChangeMessageVisibilityContext is like ChangeMessageVisibility but carries ctx into the request.
+func (q *Queue) ChangeMessageVisibilityContext(ctx context.Context, receiptHandle string, visibilityTimeout int) (resp *ChangeMessageVisibilityResponse, err error) {
 	resp = &ChangeMessageVisibilityResponse{}
 	params := makeParams("ChangeMessageVisibility")
 
 	params["VisibilityTimeout"] = strconv.Itoa(visibilityTimeout)
 	params["ReceiptHandle"] = receiptHandle
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -295,6 +456,11 @@ func (q *Queue) ChangeMessageVisibility(receiptHandle string, visibilityTimeout
 //
 // See http://goo.gl/pgffn for more details
 func (q *Queue) ChangeMessageVisibilityBatch(messageVisibilityBatch []ChangeMessageVisibilityBatchEntry) (resp *ChangeMessageVisibilityBatchResponse, err error) {
+	return q.ChangeMessageVisibilityBatchContext(context.Background(), messageVisibilityBatch)
+}
+
+// ChangeMessageVisibilityBatchContext is like ChangeMessageVisibilityBatch but carries ctx into the request.
+func (q *Queue) ChangeMessageVisibilityBatchContext(ctx context.Context, messageVisibilityBatch []ChangeMessageVisibilityBatchEntry) (resp *ChangeMessageVisibilityBatchResponse, err error) {
 	resp = &ChangeMessageVisibilityBatchResponse{}
 	params := makeParams("ChangeMessageVisibilityBatch")
 
@@ -304,7 +470,7 @@ func (q *Queue) ChangeMessageVisibilityBatch(messageVisibilityBatch []ChangeMess
 		params["ChangeMessageVisibilityBatchRequestEntry."+strconv.Itoa(i+1)+".VisibilityTimeout"] = strconv.Itoa(messageVisibility.VisibilityTimeout)
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -312,17 +478,82 @@ func (q *Queue) ChangeMessageVisibilityBatch(messageVisibilityBatch []ChangeMess
 //
 // See http://goo.gl/ThPrF for more details
 func (q *Queue) ReceiveMessage(attributes []string, maxNumberOfMessages int, visibilityTimeout int) (resp *ReceiveMessageResponse, err error) {
+	return q.ReceiveMessageContext(context.Background(), attributes, maxNumberOfMessages, visibilityTimeout)
+}
+
+// ReceiveMessageContext is like ReceiveMessage but carries ctx into the request.
+func (q *Queue) ReceiveMessageContext(ctx context.Context, attributes []string, maxNumberOfMessages int, visibilityTimeout int) (resp *ReceiveMessageResponse, err error) {
+	return q.ReceiveMessageWithParamsContext(ctx, ReceiveMessageParams{
+		AttributeNames:      systemAttributeNames(attributes),
+		MaxNumberOfMessages: maxNumberOfMessages,
+		VisibilityTimeout:   visibilityTimeout,
+		sendZeros:           true,
+	})
+}
+
+// ReceiveMessageWithParams is a version of the ReceiveMessage action that accepts every optional
+// parameter, including WaitTimeSeconds for long polling. Parameters left at zero are not sent, so
+// that the queue's own settings apply. Messages whose digests do not match are left out of the
+// response and reported by a *ReceiveChecksumError, returned along with the response.
+//
+// See http://goo.gl/ThPrF for more details
+func (q *Queue) ReceiveMessageWithParams(p ReceiveMessageParams) (resp *ReceiveMessageResponse, err error) {
+	return q.ReceiveMessageWithParamsContext(context.Background(), p)
+}
+
+// ReceiveMessageWithParamsContext is like ReceiveMessageWithParams but carries ctx into the request.
+func (q *Queue) ReceiveMessageWithParamsContext(ctx context.Context, p ReceiveMessageParams) (resp *ReceiveMessageResponse, err error) {
 	resp = &ReceiveMessageResponse{}
 	params := makeParams("ReceiveMessage")
 
-	for i, attribute := range attributes {
-		params["AttributeName."+strconv.Itoa(i+1)] = attribute
+	for i, attribute := range p.AttributeNames {
+		params["AttributeName."+strconv.Itoa(i+1)] = string(attribute)
+	}
+	for i, name := range p.MessageAttributeNames {
+		params["MessageAttributeName."+strconv.Itoa(i+1)] = name
 	}
 
-	params["MaxNumberOfMessages"] = strconv.Itoa(maxNumberOfMessages)
-	params["VisibilityTimeout"] = strconv.Itoa(visibilityTimeout)
+	if p.MaxNumberOfMessages != 0 || p.sendZeros {
+		params["MaxNumberOfMessages"] = strconv.Itoa(p.MaxNumberOfMessages)
+	}
+	if p.VisibilityTimeout != 0 || p.sendZeros {
+		params["VisibilityTimeout"] = strconv.Itoa(p.VisibilityTimeout)
+	}
+	if p.ReceiveRequestAttemptId != "" {
+		params["ReceiveRequestAttemptId"] = p.ReceiveRequestAttemptId
+	}
+
+	// Without an explicit WaitTimeSeconds the queue's own
+	// ReceiveMessageWaitTimeSeconds applies, which may be as long as the
+	// maximum, so the HTTP timeout must allow for the longest hold.
+	hold := MaxWaitTimeSeconds
+	if p.WaitTimeSeconds != 0 {
+		params["WaitTimeSeconds"] = strconv.Itoa(p.WaitTimeSeconds)
+		hold = p.WaitTimeSeconds
+	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.longQuery(ctx, q.Url, params, resp, time.Duration(hold)*time.Second)
+	if err != nil {
+		return
+	}
+	verified := resp.Messages[:0]
+	var mismatched *ReceiveChecksumError
+	for _, m := range resp.Messages {
+		checkErr := q.SQS.verifyMessage(m.MessageId, m.Body, m.MD5OfBody, m.MessageAttributes(), m.MD5OfMessageAttributes)
+		if checkErr == nil {
+			verified = append(verified, m)
+			continue
+		}
+		if mismatched == nil {
+			mismatched = &ReceiveChecksumError{Errors: make(map[string]*ChecksumError)}
+		}
+		mismatched.Messages = append(mismatched.Messages, m)
+		mismatched.Errors[m.ReceiptHandle] = checkErr.(*ChecksumError)
+	}
+	resp.Messages = verified
+	if mismatched != nil {
+		err = mismatched
+	}
 	return
 }
 
@@ -330,12 +561,17 @@ func (q *Queue) ReceiveMessage(attributes []string, maxNumberOfMessages int, vis
 //
 // See http://goo.gl/6XBv7 for more details
 func (q *Queue) DeleteMessage(receiptHandle string) (resp *DeleteMessageResponse, err error) {
+	return q.DeleteMessageContext(context.Background(), receiptHandle)
+}
+
+// DeleteMessageContext is like DeleteMessage but carries ctx into the request.
+func (q *Queue) DeleteMessageContext(ctx context.Context, receiptHandle string) (resp *DeleteMessageResponse, err error) {
 	resp = &DeleteMessageResponse{}
 	params := makeParams("DeleteMessage")
 
 	params["ReceiptHandle"] = receiptHandle
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -343,6 +579,11 @@ func (q *Queue) DeleteMessage(receiptHandle string) (resp *DeleteMessageResponse
 //
 // See http://goo.gl/y1ehG for more details
 func (q *Queue) DeleteMessageBatch(deleteMessageBatch []DeleteMessageBatch) (resp *DeleteMessageBatchResponse, err error) {
+	return q.DeleteMessageBatchContext(context.Background(), deleteMessageBatch)
+}
+
+// DeleteMessageBatchContext is like DeleteMessageBatch but carries ctx into the request.
+func (q *Queue) DeleteMessageBatchContext(ctx context.Context, deleteMessageBatch []DeleteMessageBatch) (resp *DeleteMessageBatchResponse, err error) {
 	resp = &DeleteMessageBatchResponse{}
 	params := makeParams("DeleteMessageBatch")
 
@@ -351,7 +592,7 @@ func (q *Queue) DeleteMessageBatch(deleteMessageBatch []DeleteMessageBatch) (res
 		params["DeleteMessageBatchRequestEntry."+strconv.Itoa(i+1)+".ReceiptHandle"] = deleteMessage.ReceiptHandle
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -360,42 +601,228 @@ func (q *Queue) DeleteMessageBatch(deleteMessageBatch []DeleteMessageBatch) (res
 //
 // See http://goo.gl/7OnPb for more details
 func (q *Queue) SendMessage(messageBody string) (resp *SendMessageResponse, err error) {
+	return q.SendMessageContext(context.Background(), messageBody)
+}
+
+// SendMessageContext is like SendMessage but carries ctx into the request.
+func (q *Queue) SendMessageContext(ctx context.Context, messageBody string) (resp *SendMessageResponse, err error) {
+	return q.SendMessageWithParamsContext(ctx, SendMessageParams{MessageBody: messageBody})
+}
+
+// SendMessageWithParams is a version of the SendMessage action that accepts every optional parameter,
+// including typed message attributes.
+//
+// See http://goo.gl/7OnPb for more details
+func (q *Queue) SendMessageWithParams(p SendMessageParams) (resp *SendMessageResponse, err error) {
+	return q.SendMessageWithParamsContext(context.Background(), p)
+}
+
+// SendMessageWithParamsContext is like SendMessageWithParams but carries ctx into the request.
+func (q *Queue) SendMessageWithParamsContext(ctx context.Context, p SendMessageParams) (resp *SendMessageResponse, err error) {
+	if err = q.validateFifoMessage(p.MessageGroupId, p.DelaySeconds); err != nil {
+		return nil, err
+	}
 	resp = &SendMessageResponse{}
 	params := makeParams("SendMessage")
 
-	params["MessageBody"] = messageBody
-	err = q.SQS.query(q.Url, params, resp)
+	params["MessageBody"] = p.MessageBody
+	if p.DelaySeconds != 0 {
+		params["DelaySeconds"] = strconv.Itoa(p.DelaySeconds)
+	}
+	addMessageAttributeParams(params, "MessageAttribute", p.MessageAttributes)
+	if p.MessageGroupId != "" {
+		params["MessageGroupId"] = p.MessageGroupId
+	}
+	if p.MessageDeduplicationId != "" {
+		params["MessageDeduplicationId"] = p.MessageDeduplicationId
+	}
+
+	err = q.SQS.query(ctx, q.Url, params, resp)
+	if err != nil {
+		return
+	}
+	err = q.SQS.verifyMessage(resp.MessageId, p.MessageBody, resp.MD5OfMessageBody, p.MessageAttributes, resp.MD5OfMessageAttributes)
 	return
 }
 
 // SendMessageWithDelay is a helper function for SendMessage action which delivers a message to the specified queue
-// with a delay.
+// with a delay. FIFO queues take neither a per-message delay nor a message without MessageGroupId, so use
+// SendMessageWithParams for them.
 //
 // See http://goo.gl/7OnPb for more details
 func (q *Queue) SendMessageWithDelay(messageBody string, delaySeconds int) (resp *SendMessageResponse, err error) {
+	return q.SendMessageWithDelayContext(context.Background(), messageBody, delaySeconds)
+}
+
+// SendMessageWithDelayContext is like SendMessageWithDelay but carries ctx into the request.
+func (q *Queue) SendMessageWithDelayContext(ctx context.Context, messageBody string, delaySeconds int) (resp *SendMessageResponse, err error) {
+	if err = q.validateFifoMessage("", delaySeconds); err != nil {
+		return nil, err
+	}
 	resp = &SendMessageResponse{}
 	params := makeParams("SendMessage")
 
 	params["MessageBody"] = messageBody
 	params["DelaySeconds"] = strconv.Itoa(delaySeconds)
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
+	if err != nil {
+		return
+	}
+	err = q.SQS.verifyMessage(resp.MessageId, messageBody, resp.MD5OfMessageBody, nil, resp.MD5OfMessageAttributes)
 	return
 }
 
-// SendMessageBatch action delivers up to ten messages to the specified queue.
+// SendMessageBatch action delivers up to ten messages to the specified queue. When
+// the digests of some entries do not match, the response is returned along with a
+// *BatchChecksumError naming them.
 //
 // See http://goo.gl/mNytv for more details
 func (q *Queue) SendMessageBatch(sendMessageBatchRequests []SendMessageBatchRequestEntry) (resp *SendMessageBatchResponse, err error) {
+	return q.SendMessageBatchContext(context.Background(), sendMessageBatchRequests)
+}
+
+// SendMessageBatchContext is like SendMessageBatch but carries ctx into the request.
+func (q *Queue) SendMessageBatchContext(ctx context.Context, sendMessageBatchRequests []SendMessageBatchRequestEntry) (resp *SendMessageBatchResponse, err error) {
 	resp = &SendMessageBatchResponse{}
 	params := makeParams("SendMessageBatch")
 
 	for i, sendMessageBatchRequest := range sendMessageBatchRequests {
+		if err = q.validateFifoMessage(sendMessageBatchRequest.MessageGroupId, sendMessageBatchRequest.DelaySeconds); err != nil {
+			return nil, err
+		}
 		params["SendMessageBatchRequestEntry."+strconv.Itoa(i+1)+".Id"] = sendMessageBatchRequest.Id
 		params["SendMessageBatchRequestEntry."+strconv.Itoa(i+1)+".MessageBody"] = sendMessageBatchRequest.MessageBody
-		params["SendMessageBatchRequestEntry."+strconv.Itoa(i+1)+".DelaySeconds"] = strconv.Itoa(sendMessageBatchRequest.DelaySeconds)
+		if !q.IsFifo() {
+			// FIFO queues only support a queue wide delay, see validateFifoMessage.
+			params["SendMessageBatchRequestEntry."+strconv.Itoa(i+1)+".DelaySeconds"] = strconv.Itoa(sendMessageBatchRequest.DelaySeconds)
+		}
+		addMessageAttributeParams(params, "SendMessageBatchRequestEntry."+strconv.Itoa(i+1)+".MessageAttribute", sendMessageBatchRequest.MessageAttributes)
+		if sendMessageBatchRequest.MessageGroupId != "" {
+			params["SendMessageBatchRequestEntry."+strconv.Itoa(i+1)+".MessageGroupId"] = sendMessageBatchRequest.MessageGroupId
+		}
+		if sendMessageBatchRequest.MessageDeduplicationId != "" {
+			params["SendMessageBatchRequestEntry."+strconv.Itoa(i+1)+".MessageDeduplicationId"] = sendMessageBatchRequest.MessageDeduplicationId
+		}
+	}
+
+	err = q.SQS.query(ctx, q.Url, params, resp)
+	if err != nil {
+		return
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	entries := make(map[string]SendMessageBatchRequestEntry, len(sendMessageBatchRequests))
+	for _, entry := range sendMessageBatchRequests {
+		entries[entry.Id] = entry
+	}
+	var mismatched map[string]*ChecksumError
+	for _, result := range resp.Entries {
+		entry := entries[result.Id]
+		checkErr := q.SQS.verifyMessage(result.MessageId, entry.MessageBody, result.MD5OfMessageBody, entry.MessageAttributes, result.MD5OfMessageAttributes)
+		if checkErr != nil {
+			if mismatched == nil {
+				mismatched = make(map[string]*ChecksumError)
+			}
+			mismatched[result.Id] = checkErr.(*ChecksumError)
+		}
+	}
+	if mismatched != nil {
+		err = &BatchChecksumError{mismatched}
+	}
+	return
+}
+
+// PurgeWindow is how long SQS takes to purge a queue. Another purge of the
+// same queue within this window fails with ErrPurgeQueueInProgress.
+const PurgeWindow = 60 * time.Second
+
+// DefaultPurgeRetryInterval is how often PurgeAndWait retries a purge that
+// is refused because another one is in progress.
+const DefaultPurgeRetryInterval = 5 * time.Second
+
+// Purge action deletes all the messages in the queue, keeping its URL, attributes and
+// permissions. Messages sent while the purge runs may be deleted as well. Purge fails
+// with an error matching ErrPurgeQueueInProgress, see IsPurgeInProgress, when the
+// queue was already purged within the last PurgeWindow.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_PurgeQueue.html for more details
+func (q *Queue) Purge() (resp *PurgeQueueResponse, err error) {
+	return q.PurgeContext(context.Background())
+}
+
+// PurgeContext is like Purge but carries ctx into the request.
+func (q *Queue) PurgeContext(ctx context.Context) (resp *PurgeQueueResponse, err error) {
+	resp = &PurgeQueueResponse{}
+	params := makeParams("PurgeQueue")
+
+	err = q.SQS.query(ctx, q.Url, params, resp)
+	return
+}
+
+// PurgeAndWait is like Purge, but when another purge of the queue is in progress it
+// retries every interval until that purge is over, giving up once a whole PurgeWindow
+// has passed since the first attempt. A zero interval means DefaultPurgeRetryInterval.
+func (q *Queue) PurgeAndWait(interval time.Duration) (resp *PurgeQueueResponse, err error) {
+	return q.PurgeAndWaitContext(context.Background(), interval)
+}
+
+// PurgeAndWaitContext is like PurgeAndWait but carries ctx into the requests and stops
+// waiting when ctx is done.
+func (q *Queue) PurgeAndWaitContext(ctx context.Context, interval time.Duration) (resp *PurgeQueueResponse, err error) {
+	if interval <= 0 {
+		interval = DefaultPurgeRetryInterval
+	}
+	deadline := time.Now().Add(PurgeWindow + interval)
+	for {
+		resp, err = q.PurgeContext(ctx)
+		if !IsPurgeInProgress(err) || time.Now().Add(interval).After(deadline) {
+			return
+		}
+		if debug {
+			log.Printf("purge of %s in progress, retrying in %v\n", q.Url, interval)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ListDeadLetterSourceQueues action returns the URLs of the queues that have the queue
+// configured as their dead-letter queue.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListDeadLetterSourceQueues.html for more details
+func (q *Queue) ListDeadLetterSourceQueues() (resp *ListDeadLetterSourceQueuesResponse, err error) {
+	return q.ListDeadLetterSourceQueuesContext(context.Background())
+}
+
+// ListDeadLetterSourceQueuesContext is like ListDeadLetterSourceQueues but carries ctx into the request.
+func (q *Queue) ListDeadLetterSourceQueuesContext(ctx context.Context) (resp *ListDeadLetterSourceQueuesResponse, err error) {
+	return q.ListDeadLetterSourceQueuesPageContext(ctx, 0, "")
+}
+
+// ListDeadLetterSourceQueuesPage is a version of the ListDeadLetterSourceQueues action that returns
+// at most maxResults queues, starting from the nextToken of a previous page. Use zero and an empty
+// token for the first page.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListDeadLetterSourceQueues.html for more details
+func (q *Queue) ListDeadLetterSourceQueuesPage(maxResults int, nextToken string) (resp *ListDeadLetterSourceQueuesResponse, err error) {
+	return q.ListDeadLetterSourceQueuesPageContext(context.Background(), maxResults, nextToken)
+}
+
+// ListDeadLetterSourceQueuesPageContext is like ListDeadLetterSourceQueuesPage but carries ctx into the request.
+func (q *Queue) ListDeadLetterSourceQueuesPageContext(ctx context.Context, maxResults int, nextToken string) (resp *ListDeadLetterSourceQueuesResponse, err error) {
+	resp = &ListDeadLetterSourceQueuesResponse{}
+	params := makeParams("ListDeadLetterSourceQueues")
+
+	if maxResults != 0 {
+		params["MaxResults"] = strconv.Itoa(maxResults)
+	}
+	if nextToken != "" {
+		params["NextToken"] = nextToken
+	}
+
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -403,51 +830,126 @@ func (q *Queue) SendMessageBatch(sendMessageBatchRequests []SendMessageBatchRequ
 //
 // See http://goo.gl/c3YCr for more details
 func (q *Queue) Delete() (resp *DeleteQueueResponse, err error) {
+	return q.DeleteContext(context.Background())
+}
+
+// DeleteContext is like Delete but carries ctx into the request.
+func (q *Queue) DeleteContext(ctx context.Context) (resp *DeleteQueueResponse, err error) {
 	resp = &DeleteQueueResponse{}
 	params := makeParams("DeleteQueue")
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
-// SetQueueAttributes action sets one attribute of a queue per request.
+// SetQueueAttributes action sets one or more attributes of a queue in a single request. The
+// attributes are checked before the request goes out, so none is set if any is invalid.
 //
 // See http://goo.gl/LyZnj for more details
-func (q *Queue) SetQueueAttributes(attribute Attribute) (resp *SetQueueAttributesResponse, err error) {
+func (q *Queue) SetQueueAttributes(attributes ...Attribute) (resp *SetQueueAttributesResponse, err error) {
+	return q.SetQueueAttributesContext(context.Background(), attributes...)
+}
+
+// SetQueueAttributesContext is like SetQueueAttributes but carries ctx into the request.
+func (q *Queue) SetQueueAttributesContext(ctx context.Context, attributes ...Attribute) (resp *SetQueueAttributesResponse, err error) {
+	if len(attributes) == 0 {
+		return nil, fmt.Errorf("sqs: SetQueueAttributes needs at least one attribute")
+	}
+	if err = validateQueueAttributes(attributes); err != nil {
+		return nil, err
+	}
+
 	resp = &SetQueueAttributesResponse{}
 	params := makeParams("SetQueueAttributes")
 
-	params["Attribute.Name"] = attribute.Name
-	params["Attribute.Value"] = attribute.Value
+	addAttributeParams(params, attributes)
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
-// ListQueues  action returns a list of your queues.
+// SetReceiveMessageWaitTimeSeconds is a helper function for SetQueueAttributes action that makes every
+// ReceiveMessage call on the queue long poll for up to waitTimeSeconds, unless the call sets its own
+// WaitTimeSeconds.
 //
-// See http://goo.gl/RPRWr for more details
+// See http://goo.gl/LyZnj for more details
+func (q *Queue) SetReceiveMessageWaitTimeSeconds(waitTimeSeconds int) (resp *SetQueueAttributesResponse, err error) {
+	return q.SetReceiveMessageWaitTimeSecondsContext(context.Background(), waitTimeSeconds)
+}
+
+// SetReceiveMessageWaitTimeSecondsContext is like SetReceiveMessageWaitTimeSeconds but carries ctx into the request.
+func (q *Queue) SetReceiveMessageWaitTimeSecondsContext(ctx context.Context, waitTimeSeconds int) (resp *SetQueueAttributesResponse, err error) {
+	return q.SetQueueAttributesContext(ctx, Attribute{"ReceiveMessageWaitTimeSeconds", strconv.Itoa(waitTimeSeconds)})
+}
 
+// ListQueues  action returns a list of your queues. It follows NextToken through every
+// page, so the response holds all queues of the account however many there are; use
+// ListQueuesPage or Queues to fetch them a page at a time instead.
+//
+// See http://goo.gl/RPRWr for more details
 func (s *SQS) ListQueues() (resp *ListQueuesResponse, err error) {
-	resp = &ListQueuesResponse{}
-	params := makeParams("ListQueues")
+	return s.ListQueuesContext(context.Background())
+}
 
-	err = s.query("", params, resp)
-	return
+// ListQueuesContext is like ListQueues but carries ctx into the requests.
+func (s *SQS) ListQueuesContext(ctx context.Context) (resp *ListQueuesResponse, err error) {
+	return s.ListQueuesWithPrefixContext(ctx, "")
 }
 
-// ListQueuesWithPrefix action returns only a list of queues with a name beginning with the specified value are returned
+// ListQueuesWithPrefix action returns only a list of queues with a name beginning with the specified value are returned.
+// Like ListQueues it follows NextToken through every page.
 //
 // See http://goo.gl/RPRWr for more details
 func (s *SQS) ListQueuesWithPrefix(queueNamePrefix string) (resp *ListQueuesResponse, err error) {
+	return s.ListQueuesWithPrefixContext(context.Background(), queueNamePrefix)
+}
+
+// ListQueuesWithPrefixContext is like ListQueuesWithPrefix but carries ctx into the requests.
+func (s *SQS) ListQueuesWithPrefixContext(ctx context.Context, queueNamePrefix string) (resp *ListQueuesResponse, err error) {
+	var urls []string
+	nextToken := ""
+	for {
+		// SQS only pages, and returns a NextToken, when MaxResults is set.
+		resp, err = s.ListQueuesPageContext(ctx, queueNamePrefix, listQueuesPageSize, nextToken)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, resp.QueueUrl...)
+		if resp.NextToken == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+	resp.QueueUrl = urls
+	return
+}
+
+// ListQueuesPage is a version of the ListQueues action that returns at most maxResults
+// queues with a name beginning with queueNamePrefix, starting from the nextToken of a
+// previous page. Use an empty prefix to list all queues, and zero and an empty token for
+// the first page. See Queues for an iterator that follows NextToken.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListQueues.html for more details
+func (s *SQS) ListQueuesPage(queueNamePrefix string, maxResults int, nextToken string) (resp *ListQueuesResponse, err error) {
+	return s.ListQueuesPageContext(context.Background(), queueNamePrefix, maxResults, nextToken)
+}
+
+// ListQueuesPageContext is like ListQueuesPage but carries ctx into the request.
+func (s *SQS) ListQueuesPageContext(ctx context.Context, queueNamePrefix string, maxResults int, nextToken string) (resp *ListQueuesResponse, err error) {
 	resp = &ListQueuesResponse{}
 	params := makeParams("ListQueues")
 
 	if queueNamePrefix != "" {
 		params["QueueNamePrefix"] = queueNamePrefix
 	}
+	if maxResults != 0 {
+		params["MaxResults"] = strconv.Itoa(maxResults)
+	}
+	if nextToken != "" {
+		params["NextToken"] = nextToken
+	}
 
-	err = s.query("", params, resp)
+	err = s.query(ctx, "", params, resp)
 	return
 }
 
@@ -455,7 +957,12 @@ func (s *SQS) ListQueuesWithPrefix(queueNamePrefix string) (resp *ListQueuesResp
 //
 // See http://goo.gl/hk7Iu for more details
 func (s *SQS) GetQueue(queueName string) (queue *Queue, err error) {
-	resp, err := s.GetQueueUrl(queueName)
+	return s.GetQueueContext(context.Background(), queueName)
+}
+
+// GetQueueContext is like GetQueue but carries ctx into the request.
+func (s *SQS) GetQueueContext(ctx context.Context, queueName string) (queue *Queue, err error) {
+	resp, err := s.GetQueueUrlContext(ctx, queueName)
 	if err != nil {
 		return nil, err
 	}
@@ -469,7 +976,12 @@ func (s *SQS) GetQueue(queueName string) (queue *Queue, err error) {
 //
 // See http://goo.gl/hk7Iu for more details
 func (s *SQS) GetQueueOfOwner(queueName, queueOwnerAWSAccountId string) (queue *Queue, err error) {
-	resp, err := s.GetQueueUrlOfOwner(queueName, queueOwnerAWSAccountId)
+	return s.GetQueueOfOwnerContext(context.Background(), queueName, queueOwnerAWSAccountId)
+}
+
+// GetQueueOfOwnerContext is like GetQueueOfOwner but carries ctx into the request.
+func (s *SQS) GetQueueOfOwnerContext(ctx context.Context, queueName, queueOwnerAWSAccountId string) (queue *Queue, err error) {
+	resp, err := s.GetQueueUrlOfOwnerContext(ctx, queueName, queueOwnerAWSAccountId)
 	if err != nil {
 		return nil, err
 	}
@@ -483,12 +995,17 @@ func (s *SQS) GetQueueOfOwner(queueName, queueOwnerAWSAccountId string) (queue *
 //
 // See http://goo.gl/hk7Iu for more details
 func (s *SQS) GetQueueUrl(queueName string) (resp *GetQueueUrlResponse, err error) {
+	return s.GetQueueUrlContext(context.Background(), queueName)
+}
+
+// GetQueueUrlContext is like GetQueueUrl but carries ctx into the request.
+func (s *SQS) GetQueueUrlContext(ctx context.Context, queueName string) (resp *GetQueueUrlResponse, err error) {
 	resp = &GetQueueUrlResponse{}
 	params := makeParams("GetQueueUrl")
 
 	params["QueueName"] = queueName
 
-	err = s.query("", params, resp)
+	err = s.query(ctx, "", params, resp)
 	return
 }
 
@@ -497,6 +1014,11 @@ func (s *SQS) GetQueueUrl(queueName string) (resp *GetQueueUrlResponse, err erro
 //
 // See http://goo.gl/hk7Iu for more details for more details
 func (s *SQS) GetQueueUrlOfOwner(queueName, queueOwnerAWSAccountId string) (resp *GetQueueUrlResponse, err error) {
+	return s.GetQueueUrlOfOwnerContext(context.Background(), queueName, queueOwnerAWSAccountId)
+}
+
+// GetQueueUrlOfOwnerContext is like GetQueueUrlOfOwner but carries ctx into the request.
+func (s *SQS) GetQueueUrlOfOwnerContext(ctx context.Context, queueName, queueOwnerAWSAccountId string) (resp *GetQueueUrlResponse, err error) {
 	resp = &GetQueueUrlResponse{}
 	params := makeParams("GetQueueUrl")
 
@@ -506,35 +1028,56 @@ func (s *SQS) GetQueueUrlOfOwner(queueName, queueOwnerAWSAccountId string) (resp
 		params["QueueOwnerAWSAccountId"] = queueOwnerAWSAccountId
 	}
 
-	err = s.query("", params, resp)
+	err = s.query(ctx, "", params, resp)
 	return
 }
 
-func (s *SQS) query(queueUrl string, params map[string]string, resp interface{}) error {
-	params["Version"] = "2011-10-01"
-	params["Timestamp"] = time.Now().In(time.UTC).Format(time.RFC3339)
-	var endpoint *url.URL
-	var path string
-	var err error
-	if queueUrl != "" {
-		endpoint, err = url.Parse(queueUrl)
-		path = queueUrl[len(s.Region.SQSEndpoint):]
-	} else {
-		endpoint, err = url.Parse(s.Region.SQSEndpoint)
-		path = "/"
+// query sends the action described by params and decodes its response into
+// resp, retrying failed attempts as allowed by the handle's RetryPolicy. If
+// ctx is done before the response arrives, ctx.Err() is returned.
+func (s *SQS) query(ctx context.Context, queueUrl string, params map[string]string, resp interface{}) error {
+	return s.longQuery(ctx, queueUrl, params, resp, 0)
+}
+
+// longQuery is like query for actions the server may hold for up to hold
+// before answering. The HTTP client timeout is extended to cover it.
+func (s *SQS) longQuery(ctx context.Context, queueUrl string, params map[string]string, resp interface{}, hold time.Duration) error {
+	client := s.httpClient(hold)
+	for attempt := 1; ; attempt++ {
+		err := s.send(ctx, client, queueUrl, params, resp)
+		if err == nil || attempt >= s.Retry.MaxAttempts || !IsRetryable(err) {
+			return err
+		}
+		if debug {
+			log.Printf("attempt %d failed, retrying: %v\n", attempt, err)
+		}
+		select {
+		case <-time.After(s.Retry.delay(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// send makes a single, freshly signed attempt at the request.
+func (s *SQS) send(ctx context.Context, client *http.Client, queueUrl string, params map[string]string, resp interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
+	req, err := s.newRequest(queueUrl, params)
 	if err != nil {
 		return err
 	}
-
-	sign(s.Auth, "GET", path, params, endpoint.Host)
-	endpoint.RawQuery = multimap(params).Encode()
+	req = req.WithContext(ctx)
 	if debug {
-		log.Printf("get { %v } -> {\n", endpoint.String())
+		log.Printf("%s { %v } -> {\n", req.Method, req.URL.String())
 	}
 
-	r, err := http.Get(endpoint.String())
+	r, err := client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		return err
 	}
 	defer r.Body.Close()
@@ -548,9 +1091,83 @@ func (s *SQS) query(queueUrl string, params map[string]string, resp interface{})
 		return buildError(r)
 	}
 	err = xml.NewDecoder(r.Body).Decode(resp)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
 	return err
 }
 
+// Time allowed for a held request on top of the hold itself.
+const longPollSlack = 10 * time.Second
+
+// httpClient returns the client requests are sent with. If its timeout is
+// too short for a request held for hold, a copy with a longer timeout is
+// returned instead.
+func (s *SQS) httpClient(hold time.Duration) *http.Client {
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if hold > 0 && client.Timeout != 0 && client.Timeout < hold+longPollSlack {
+		extended := *client
+		extended.Timeout = hold + longPollSlack
+		client = &extended
+	}
+	return client
+}
+
+// newRequest builds the signed HTTP request carrying params to queueUrl, or to
+// the region endpoint when queueUrl is empty. Parameters travel in a form
+// encoded body unless the handle was configured to use GET. The params map
+// itself is left untouched so that it can be signed again on a retry.
+func (s *SQS) newRequest(queueUrl string, actionParams map[string]string) (*http.Request, error) {
+	params := make(map[string]string, len(actionParams)+8)
+	for k, v := range actionParams {
+		params[k] = v
+	}
+	params["Version"] = APIVersion
+	rawUrl := queueUrl
+	if rawUrl == "" {
+		rawUrl = s.Region.SQSEndpoint
+	}
+	endpoint, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	method := s.Method
+	if method == "" {
+		method = "POST"
+	}
+	if s.SignatureVersion == SignatureV2 {
+		path := endpoint.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		params["Timestamp"] = time.Now().In(time.UTC).Format(time.RFC3339)
+		signV2(s.Auth, method, path, params, endpoint.Host)
+	}
+
+	if method == "GET" {
+		endpoint.RawQuery = multimap(params).Encode()
+		if s.SignatureVersion != SignatureV2 {
+			newV4Signer(s.Auth, s.regionName(), time.Now()).presign(method, endpoint)
+		}
+		return http.NewRequest(method, endpoint.String(), nil)
+	}
+
+	body := []byte(multimap(params).Encode())
+	req, err := http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	if s.SignatureVersion != SignatureV2 {
+		newV4Signer(s.Auth, s.regionName(), time.Now()).sign(req, body)
+	}
+	return req, nil
+}
+
 func multimap(p map[string]string) url.Values {
 	q := make(url.Values, len(p))
 	for k, v := range p {
@@ -562,16 +1179,30 @@ func multimap(p map[string]string) url.Values {
 func buildError(r *http.Response) error {
 	errors := xmlErrors{}
 	xml.NewDecoder(r.Body).Decode(&errors)
-	var err Error
-	if len(errors.Errors) > 0 {
-		err = errors.Errors[0]
+	all := errors.Error
+	if len(all) == 0 {
+		all = errors.Errors
 	}
-	err.RequestId = errors.RequestId
-	err.StatusCode = r.StatusCode
-	if err.Message == "" {
-		err.Message = r.Status
+	if len(all) == 0 {
+		all = []Error{{}}
+	}
+	requestId := errors.RequestId
+	if requestId == "" {
+		requestId = errors.LegacyRequestId
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		err := &all[i]
+		err.RequestId = requestId
+		err.StatusCode = r.StatusCode
+		if err.Message == "" {
+			err.Message = r.Status
+		}
+		if i+1 < len(all) {
+			err.Next = &all[i+1]
+		}
 	}
-	return &err
+	return &all[0]
 }
 
 func makeParams(action string) map[string]string {
@@ -0,0 +1,84 @@
+package sqs
+
+import (
+	"context"
+)
+
+// Server-side encryption schemes reported by QueueAttributes.Encryption.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-server-side-encryption.html for more details
+const (
+	EncryptionNone = ""        // Messages are stored unencrypted
+	EncryptionSQS  = "SSE-SQS" // Encryption keys managed by SQS
+	EncryptionKMS  = "SSE-KMS" // Encryption keys managed by AWS KMS
+)
+
+// encryptionAttributes are the attributes Encryption looks at.
+var encryptionAttributes = []string{"KmsMasterKeyId", "SqsManagedSseEnabled"}
+
+// Encryption returns the server-side encryption scheme of the queue, one of
+// EncryptionNone, EncryptionSQS and EncryptionKMS.
+func (a *QueueAttributes) Encryption() string {
+	switch {
+	case a.KmsMasterKeyId != "":
+		return EncryptionKMS
+	case a.SqsManagedSseEnabled:
+		return EncryptionSQS
+	}
+	return EncryptionNone
+}
+
+// Encrypted reports whether the queue stores its messages encrypted.
+func (a *QueueAttributes) Encrypted() bool {
+	return a.Encryption() != EncryptionNone
+}
+
+// Encryption is a helper function for GetQueueAttributes action that returns the server-side
+// encryption scheme of the queue, one of EncryptionNone, EncryptionSQS and EncryptionKMS.
+func (q *Queue) Encryption() (string, error) {
+	return q.EncryptionContext(context.Background())
+}
+
+// EncryptionContext is like Encryption but carries ctx into the request.
+func (q *Queue) EncryptionContext(ctx context.Context) (string, error) {
+	resp, err := q.GetQueueAttributesContext(ctx, encryptionAttributes)
+	if err != nil {
+		return "", err
+	}
+	attrs, err := resp.QueueAttributes()
+	if err != nil {
+		return "", err
+	}
+	return attrs.Encryption(), nil
+}
+
+// UnencryptedQueues is a helper function for ListQueues and GetQueueAttributes actions that
+// returns the queues whose name begins with queueNamePrefix and that do not use server-side
+// encryption. An empty prefix audits every queue of the account. Queues deleted while the
+// audit runs are skipped.
+func (s *SQS) UnencryptedQueues(queueNamePrefix string) ([]*Queue, error) {
+	return s.UnencryptedQueuesContext(context.Background(), queueNamePrefix)
+}
+
+// UnencryptedQueuesContext is like UnencryptedQueues but carries ctx into the requests.
+func (s *SQS) UnencryptedQueuesContext(ctx context.Context, queueNamePrefix string) ([]*Queue, error) {
+	var queues []*Queue
+	it := s.QueuesContext(ctx, queueNamePrefix)
+	for it.Next() {
+		q := it.Queue()
+		encryption, err := q.EncryptionContext(ctx)
+		if IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if encryption == EncryptionNone {
+			queues = append(queues, q)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return queues, nil
+}
@@ -0,0 +1,118 @@
+package sqs
+
+import (
+	"strconv"
+	"time"
+)
+
+// MessageSystemAttributeName names a system attribute SQS attaches to
+// messages, to be requested through ReceiveMessageParams.AttributeNames.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ReceiveMessage.html for more details
+type MessageSystemAttributeName string
+
+const (
+	SystemAttributeAll                              MessageSystemAttributeName = "All"
+	SystemAttributeSenderId                         MessageSystemAttributeName = "SenderId"
+	SystemAttributeSentTimestamp                    MessageSystemAttributeName = "SentTimestamp"
+	SystemAttributeApproximateReceiveCount          MessageSystemAttributeName = "ApproximateReceiveCount"
+	SystemAttributeApproximateFirstReceiveTimestamp MessageSystemAttributeName = "ApproximateFirstReceiveTimestamp"
+	SystemAttributeAWSTraceHeader                   MessageSystemAttributeName = "AWSTraceHeader"
+	SystemAttributeDeadLetterQueueSourceArn         MessageSystemAttributeName = "DeadLetterQueueSourceArn"
+
+	// FIFO queues only.
+	SystemAttributeSequenceNumber         MessageSystemAttributeName = "SequenceNumber"
+	SystemAttributeMessageDeduplicationId MessageSystemAttributeName = "MessageDeduplicationId"
+	SystemAttributeMessageGroupId         MessageSystemAttributeName = "MessageGroupId"
+)
+
+func systemAttributeNames(names []string) []MessageSystemAttributeName {
+	if names == nil {
+		return nil
+	}
+	typed := make([]MessageSystemAttributeName, len(names))
+	for i, name := range names {
+		typed[i] = MessageSystemAttributeName(name)
+	}
+	return typed
+}
+
+// SystemAttribute returns the value of the system attribute name of m, and
+// whether it was returned by ReceiveMessage. The typed accessors, such as
+// SentTimestamp, return a zero value instead when the attribute is missing
+// or malformed.
+func (m *Message) SystemAttribute(name MessageSystemAttributeName) (string, bool) {
+	for _, attribute := range m.Attribute {
+		if attribute.Name == string(name) {
+			return attribute.Value, true
+		}
+	}
+	return "", false
+}
+
+func (m *Message) stringAttribute(name MessageSystemAttributeName) string {
+	value, _ := m.SystemAttribute(name)
+	return value
+}
+
+func (m *Message) intAttribute(name MessageSystemAttributeName) int {
+	n, _ := strconv.Atoi(m.stringAttribute(name))
+	return n
+}
+
+// timeAttribute decodes a timestamp given in milliseconds since the epoch.
+func (m *Message) timeAttribute(name MessageSystemAttributeName) time.Time {
+	ms, err := strconv.ParseInt(m.stringAttribute(name), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// SenderId returns the AWS account or IP address that sent m.
+func (m *Message) SenderId() string {
+	return m.stringAttribute(SystemAttributeSenderId)
+}
+
+// SentTimestamp returns when m was sent to the queue.
+func (m *Message) SentTimestamp() time.Time {
+	return m.timeAttribute(SystemAttributeSentTimestamp)
+}
+
+// ApproximateReceiveCount returns how many times m has been received,
+// this time included.
+func (m *Message) ApproximateReceiveCount() int {
+	return m.intAttribute(SystemAttributeApproximateReceiveCount)
+}
+
+// ApproximateFirstReceiveTimestamp returns when m was first received.
+func (m *Message) ApproximateFirstReceiveTimestamp() time.Time {
+	return m.timeAttribute(SystemAttributeApproximateFirstReceiveTimestamp)
+}
+
+// AWSTraceHeader returns the X-Ray trace header sent with m.
+func (m *Message) AWSTraceHeader() string {
+	return m.stringAttribute(SystemAttributeAWSTraceHeader)
+}
+
+// DeadLetterQueueSourceArn returns the ARN of the queue m was moved from,
+// when m is in a dead-letter queue.
+func (m *Message) DeadLetterQueueSourceArn() string {
+	return m.stringAttribute(SystemAttributeDeadLetterQueueSourceArn)
+}
+
+// SequenceNumber returns the position of m in its FIFO queue.
+func (m *Message) SequenceNumber() string {
+	return m.stringAttribute(SystemAttributeSequenceNumber)
+}
+
+// MessageDeduplicationId returns the deduplication id m was sent with to a
+// FIFO queue.
+func (m *Message) MessageDeduplicationId() string {
+	return m.stringAttribute(SystemAttributeMessageDeduplicationId)
+}
+
+// MessageGroupId returns the group m belongs to in a FIFO queue.
+func (m *Message) MessageGroupId() string {
+	return m.stringAttribute(SystemAttributeMessageGroupId)
+}
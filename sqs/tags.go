@@ -0,0 +1,112 @@
+package sqs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+)
+
+// Tag is a cost allocation tag of a queue.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Response to a TagQueue request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_TagQueue.html for more details
+type TagQueueResponse struct {
+	ResponseMetadata
+}
+
+// Response to an UntagQueue request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_UntagQueue.html for more details
+type UntagQueueResponse struct {
+	ResponseMetadata
+}
+
+// Response to a ListQueueTags request.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListQueueTags.html for more details
+type ListQueueTagsResponse struct {
+	Tag []Tag `xml:"ListQueueTagsResult>Tag"`
+	ResponseMetadata
+}
+
+// Tags returns the tags of the queue keyed by tag key.
+func (r *ListQueueTagsResponse) Tags() map[string]string {
+	tags := make(map[string]string, len(r.Tag))
+	for _, t := range r.Tag {
+		tags[t.Key] = t.Value
+	}
+	return tags
+}
+
+// TagQueue action adds tags to the queue, replacing the value of any tag that is already set.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_TagQueue.html for more details
+func (q *Queue) TagQueue(tags map[string]string) (resp *TagQueueResponse, err error) {
+	return q.TagQueueContext(context.Background(), tags)
+}
+
+// TagQueueContext is like TagQueue but carries ctx into the request.
+func (q *Queue) TagQueueContext(ctx context.Context, tags map[string]string) (resp *TagQueueResponse, err error) {
+	resp = &TagQueueResponse{}
+	params := makeParams("TagQueue")
+
+	addTagParams(params, tags)
+
+	err = q.SQS.query(ctx, q.Url, params, resp)
+	return
+}
+
+// UntagQueue action removes the tags with the given keys from the queue.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_UntagQueue.html for more details
+func (q *Queue) UntagQueue(keys []string) (resp *UntagQueueResponse, err error) {
+	return q.UntagQueueContext(context.Background(), keys)
+}
+
+// UntagQueueContext is like UntagQueue but carries ctx into the request.
+func (q *Queue) UntagQueueContext(ctx context.Context, keys []string) (resp *UntagQueueResponse, err error) {
+	resp = &UntagQueueResponse{}
+	params := makeParams("UntagQueue")
+
+	for i, key := range keys {
+		params["TagKey."+strconv.Itoa(i+1)] = key
+	}
+
+	err = q.SQS.query(ctx, q.Url, params, resp)
+	return
+}
+
+// ListQueueTags action returns the tags of the queue.
+//
+// See https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListQueueTags.html for more details
+func (q *Queue) ListQueueTags() (resp *ListQueueTagsResponse, err error) {
+	return q.ListQueueTagsContext(context.Background())
+}
+
+// ListQueueTagsContext is like ListQueueTags but carries ctx into the request.
+func (q *Queue) ListQueueTagsContext(ctx context.Context) (resp *ListQueueTagsResponse, err error) {
+	resp = &ListQueueTagsResponse{}
+	params := makeParams("ListQueueTags")
+
+	err = q.SQS.query(ctx, q.Url, params, resp)
+	return
+}
+
+// addTagParams encodes tags into params as Tag.N.Key and Tag.N.Value, in
+// key order so that requests are reproducible.
+func addTagParams(params map[string]string, tags map[string]string) {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		params["Tag."+strconv.Itoa(i+1)+".Key"] = key
+		params["Tag."+strconv.Itoa(i+1)+".Value"] = tags[key]
+	}
+}
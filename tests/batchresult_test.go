@@ -0,0 +1,64 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+)
+
+func (s *S) TestSendMessageBatchPartialFailure(c *C) {
+	testServer.PrepareResponse(200, nil, TestSendMessageBatchPartialXmlOK)
+
+	entries := []sqs.SendMessageBatchRequestEntry{
+		{Id: "test_msg_001", MessageBody: "test message body 1"},
+		{Id: "test_msg_002", MessageBody: "test message body 2"},
+		{Id: "test_msg_003", MessageBody: "test message body 3"},
+	}
+	resp, err := s.queue().SendMessageBatch(entries)
+	testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.Entries, HasLen, 1)
+	c.Assert(resp.Failed, DeepEquals, []sqs.BatchResultErrorEntry{{
+		Id:          "test_msg_002",
+		Code:        "InternalError",
+		Message:     "We encountered an internal error. Please try again.",
+		SenderFault: false,
+	}})
+	c.Assert(resp.Failed[0], ErrorMatches, `test_msg_002: We encountered an internal error\. Please try again\. \(InternalError\)`)
+	c.Assert(resp.FailedEntries(entries), DeepEquals, entries[1:2])
+	c.Assert(resp.UnaccountedEntries(entries), DeepEquals, entries[2:])
+}
+
+func (s *S) TestChangeMessageVisibilityBatchPartialFailure(c *C) {
+	testServer.PrepareResponse(200, nil, TestChangeMessageVisibilityBatchPartialXmlOK)
+
+	entries := []sqs.ChangeMessageVisibilityBatchEntry{
+		{Id: "change_visibility_msg_2", ReceiptHandle: "handle2", VisibilityTimeout: 45},
+		{Id: "change_visibility_msg_3", ReceiptHandle: "handle3", VisibilityTimeout: 45},
+	}
+	resp, err := s.queue().ChangeMessageVisibilityBatch(entries)
+	testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.Id, DeepEquals, []string{"change_visibility_msg_2"})
+	c.Assert(resp.Failed, HasLen, 1)
+	c.Assert(resp.Failed[0].SenderFault, Equals, true)
+	c.Assert(resp.FailedEntries(entries), DeepEquals, entries[1:])
+	c.Assert(resp.UnaccountedEntries(entries), HasLen, 0)
+}
+
+func (s *S) TestDeleteMessageBatchUnaccounted(c *C) {
+	testServer.PrepareResponse(200, nil, TestDeleteMessageBatchXmlOK)
+
+	entries := []sqs.DeleteMessageBatch{
+		{Id: "msg1", ReceiptHandle: "handle1"},
+		{Id: "msg2", ReceiptHandle: "handle2"},
+		{Id: "msg3", ReceiptHandle: "handle3"},
+	}
+	resp, err := s.queue().DeleteMessageBatch(entries)
+	testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.FailedEntries(entries), HasLen, 0)
+	c.Assert(resp.UnaccountedEntries(entries), DeepEquals, entries[2:])
+}
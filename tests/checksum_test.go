@@ -0,0 +1,95 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+)
+
+func (s *S) TestMessageBodyMD5(c *C) {
+	c.Assert(sqs.MessageBodyMD5("This is a test message"), Equals, "fafb00f5732ab283681e124bf8747ed1")
+}
+
+func (s *S) TestSendMessageBadBodyDigest(c *C) {
+	testServer.PrepareResponse(200, nil, TestSendMessageXmlOK)
+
+	_, err := s.queue().SendMessage("This is another test message")
+	testServer.WaitRequest()
+
+	c.Assert(sqs.IsChecksumError(err), Equals, true)
+	checksumErr := err.(*sqs.ChecksumError)
+	c.Assert(checksumErr.Part, Equals, sqs.ChecksumBody)
+	c.Assert(checksumErr.Actual, Equals, "fafb00f5732ab283681e124bf8747ed1")
+	c.Assert(checksumErr.Expected, Equals, sqs.MessageBodyMD5("This is another test message"))
+	c.Assert(err, ErrorMatches, `sqs: MD5 of body of message 5fea7756-0ea4-451a-a703-a558b933e274 is "fafb00f5732ab283681e124bf8747ed1", expected ".*"`)
+}
+
+func (s *S) TestSendMessageWithDelayBadBodyDigest(c *C) {
+	testServer.PrepareResponse(200, nil, TestSendMessageXmlOK)
+
+	_, err := s.queue().SendMessageWithDelay("This is another test message", 5)
+	testServer.WaitRequest()
+
+	c.Assert(sqs.IsChecksumError(err), Equals, true)
+}
+
+func (s *S) TestSendMessageBatchBadBodyDigest(c *C) {
+	fake := newFakeSQS()
+	fake.badDigestBody = "two"
+	defer fake.Close()
+
+	resp, err := fake.queue().SendMessageBatch([]sqs.SendMessageBatchRequestEntry{
+		{Id: "1", MessageBody: "one"},
+		{Id: "2", MessageBody: "two"},
+		{Id: "3", MessageBody: "three"},
+	})
+
+	c.Assert(sqs.IsChecksumError(err), Equals, true)
+	batchErr := err.(*sqs.BatchChecksumError)
+	c.Assert(batchErr.Entries, HasLen, 1)
+	c.Assert(batchErr.Entries["2"].MessageId, Equals, "sent-2")
+	c.Assert(err, ErrorMatches, `sqs: checksum mismatch for batch entries 2: sqs: MD5 of body of message sent-2 .*`)
+	c.Assert(resp.Entries, HasLen, 3)
+}
+
+func (s *S) TestReceiveMessageBadBodyDigest(c *C) {
+	testServer.PrepareResponse(200, nil, TestReceiveMessageCorruptedXmlOK)
+
+	resp, err := s.queue().ReceiveMessage(nil, 1, 30)
+	testServer.WaitRequest()
+
+	c.Assert(sqs.IsChecksumError(err), Equals, true)
+	c.Assert(resp.Messages, HasLen, 0)
+	receiveErr := err.(*sqs.ReceiveChecksumError)
+	c.Assert(receiveErr.Messages, HasLen, 1)
+	handle := receiveErr.Messages[0].ReceiptHandle
+	c.Assert(receiveErr.Errors[handle].MessageId, Equals, "5fea7756-0ea4-451a-a703-a558b933e274")
+}
+
+func (s *S) TestReceiveMessageKeepsVerifiedMessages(c *C) {
+	fake := newFakeSQS("one", "two", "three")
+	fake.badDigestBody = "two"
+	defer fake.Close()
+
+	resp, err := fake.queue().ReceiveMessageWithParams(sqs.ReceiveMessageParams{MaxNumberOfMessages: 10})
+
+	c.Assert(err, ErrorMatches, `sqs: checksum mismatch for 1 received messages: sqs: MD5 of body of message msg-2 .*`)
+	c.Assert(resp.Messages, HasLen, 2)
+	c.Assert(resp.Messages[0].Body, Equals, "one")
+	c.Assert(resp.Messages[1].Body, Equals, "three")
+	receiveErr := err.(*sqs.ReceiveChecksumError)
+	c.Assert(receiveErr.Messages[0].Body, Equals, "two")
+	c.Assert(receiveErr.Errors["handle-2"].Part, Equals, sqs.ChecksumBody)
+}
+
+func (s *S) TestDisableChecksums(c *C) {
+	client := sqs.New(s.sqs.Auth, s.sqs.Region)
+	client.DisableChecksums = true
+	testServer.PrepareResponse(200, nil, TestReceiveMessageCorruptedXmlOK)
+
+	q := &sqs.Queue{SQS: client, Url: testServer.URL + "/123456789012/testQueue"}
+	resp, err := q.ReceiveMessage(nil, 1, 30)
+	testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.Messages[0].Body, Equals, "This is a test massage")
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"launchpad.net/goamz/aws"
+	. "launchpad.net/gocheck"
+	"net/http"
+	"sdk/sqs/sqs"
+	"time"
+)
+
+var _ = Suite(&S{})
+
+type S struct {
+	HTTPSuite
+	sqs *sqs.SQS
+}
+
+func (s *S) SetUpSuite(c *C) {
+	s.HTTPSuite.SetUpSuite(c)
+	auth := aws.Auth{AccessKey: "abc", SecretKey: "123"}
+	s.sqs = sqs.New(auth, aws.Region{Name: "us-west-2", SQSEndpoint: testServer.URL})
+}
+
+func (s *S) TestSendMessageUsesPostBody(c *C) {
+	testServer.PrepareResponse(200, nil, TestSendMessageXmlOK)
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	_, err := q.SendMessage("This is a test message")
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/123456789012/testQueue")
+	c.Assert(req.URL.RawQuery, Equals, "")
+	c.Assert(req.Header.Get("Content-Type"), Equals, "application/x-www-form-urlencoded; charset=utf-8")
+	c.Assert(req.PostForm["MessageBody"], DeepEquals, []string{"This is a test message"})
+}
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (s *S) TestNewWithClient(c *C) {
+	transport := &countingTransport{}
+	client := sqs.NewWithClient(s.sqs.Auth, s.sqs.Region, &http.Client{Transport: transport})
+	testServer.PrepareResponse(200, nil, TestCreateQueueXmlOK)
+	testServer.PrepareResponse(200, nil, TestSendMessageXmlOK)
+
+	q, err := client.CreateQueue("testQueue", nil)
+	testServer.WaitRequest()
+	c.Assert(err, IsNil)
+	c.Assert(q.Url, Equals, "http://sqs.us-east-1.amazonaws.com/123456789012/testQueue")
+
+	q.Url = testServer.URL + "/123456789012/testQueue"
+	_, err = q.SendMessage("This is a test message")
+	testServer.WaitRequest()
+	c.Assert(err, IsNil)
+	c.Assert(transport.requests, Equals, 2)
+}
+
+func (s *S) TestContextCanceled(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.sqs.ListQueuesContext(ctx)
+	c.Assert(err, Equals, context.Canceled)
+}
+
+func (s *S) TestContextDeadline(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	_, err := q.ReceiveMessageContext(ctx, nil, 1, 30)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+
+	// Release the handler still waiting on a response.
+	testServer.WaitRequest()
+	testServer.PrepareResponse(200, nil, "")
+}
+
+func (s *S) TestRetryTransientError(c *C) {
+	client := sqs.New(s.sqs.Auth, s.sqs.Region)
+	client.Retry = sqs.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	testServer.PrepareResponse(503, nil, TestServiceUnavailableXml)
+	testServer.PrepareResponse(200, nil, TestListQueuesXmlOK)
+
+	resp, err := client.ListQueues()
+	first := testServer.WaitRequest()
+	second := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.QueueUrl, HasLen, 1)
+	c.Assert(first.Form["Action"], DeepEquals, []string{"ListQueues"})
+	c.Assert(second.Form["Action"], DeepEquals, []string{"ListQueues"})
+}
+
+func (s *S) TestRetryGivesUp(c *C) {
+	client := sqs.New(s.sqs.Auth, s.sqs.Region)
+	client.Retry = sqs.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	testServer.PrepareResponse(503, nil, TestServiceUnavailableXml)
+	testServer.PrepareResponse(503, nil, TestServiceUnavailableXml)
+
+	_, err := client.ListQueues()
+	testServer.WaitRequest()
+	testServer.WaitRequest()
+
+	c.Assert(err, NotNil)
+	c.Assert(err.(*sqs.Error).StatusCode, Equals, 503)
+	c.Assert(err.(*sqs.Error).Code, Equals, "ServiceUnavailable")
+}
+
+func (s *S) TestNoRetryOnClientError(c *C) {
+	testServer.PrepareResponse(400, nil, TestInvalidParameterValueXml)
+	// A retry of the first request would consume this response.
+	testServer.PrepareResponse(200, nil, TestCreateQueueXmlOK)
+
+	_, err := s.sqs.CreateQueue("quename_nonalpha", nil)
+	testServer.WaitRequest()
+
+	c.Assert(err, NotNil)
+	c.Assert(err.(*sqs.Error).Code, Equals, "InvalidParameterValue")
+
+	_, err = s.sqs.CreateQueue("testQueue", nil)
+	testServer.WaitRequest()
+	c.Assert(err, IsNil)
+}
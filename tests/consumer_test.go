@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"sort"
+	"sync"
+	"time"
+)
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return cond()
+}
+
+func (s *S) TestConsumer(c *C) {
+	fake := newFakeSQS("one", "two", "three", "fail", "panic")
+	defer fake.Close()
+
+	var mu sync.Mutex
+	var handled, succeeded, failed []string
+	var started, stopped bool
+	consumer := sqs.NewConsumer(fake.queue(), func(ctx context.Context, m *sqs.Message) error {
+		mu.Lock()
+		handled = append(handled, m.Body)
+		mu.Unlock()
+		switch m.Body {
+		case "fail":
+			return errors.New("cannot handle")
+		case "panic":
+			panic("boom")
+		}
+		return nil
+	})
+	consumer.Pollers = 2
+	consumer.Workers = 3
+	consumer.Receive.MaxNumberOfMessages = 2
+	consumer.Hooks = sqs.ConsumerHooks{
+		OnStart: func() { started = true },
+		OnStop:  func() { stopped = true },
+		OnSuccess: func(m *sqs.Message) {
+			mu.Lock()
+			succeeded = append(succeeded, m.Body)
+			mu.Unlock()
+		},
+		OnFailure: func(m *sqs.Message, err error) {
+			mu.Lock()
+			failed = append(failed, m.Body+": "+err.Error())
+			mu.Unlock()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- consumer.Run(ctx) }()
+	c.Assert(waitFor(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(succeeded)+len(failed) == 5
+	}), Equals, true)
+	cancel()
+	c.Assert(<-done, IsNil)
+
+	sort.Strings(handled)
+	sort.Strings(succeeded)
+	sort.Strings(failed)
+	c.Assert(handled, DeepEquals, []string{"fail", "one", "panic", "three", "two"})
+	c.Assert(succeeded, DeepEquals, []string{"one", "three", "two"})
+	c.Assert(failed, DeepEquals, []string{"fail: cannot handle", "panic: sqs: handler panicked: boom"})
+	c.Assert(fake.deletedHandles(), HasLen, 3)
+	c.Assert(started, Equals, true)
+	c.Assert(stopped, Equals, true)
+}
+
+func (s *S) TestConsumerReceiveErrors(c *C) {
+	fake := newFakeSQS("one")
+	fake.failReceives = 2
+	defer fake.Close()
+
+	q := fake.queue()
+	q.Retry = sqs.NoRetry
+	handled := make(chan string, 1)
+	consumer := sqs.NewConsumer(q, func(ctx context.Context, m *sqs.Message) error {
+		handled <- m.Body
+		return nil
+	})
+	consumer.ErrorBackoff = time.Millisecond
+	consumer.Errors = make(chan *sqs.ConsumerError, 2)
+	var hookErrors int
+	consumer.Hooks.OnError = func(err *sqs.ConsumerError) { hookErrors++ }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- consumer.Run(ctx) }()
+	c.Assert(<-handled, Equals, "one")
+	cancel()
+	c.Assert(<-done, IsNil)
+
+	c.Assert(hookErrors, Equals, 2)
+	err := <-consumer.Errors
+	c.Assert(err.Op, Equals, "receive")
+	c.Assert(sqs.IsRetryable(err), Equals, true)
+	c.Assert(err, ErrorMatches, "sqs: receive failed: .*")
+}
+
+func (s *S) TestConsumerChecksumMismatch(c *C) {
+	fake := newFakeSQS("one", "corrupted", "two")
+	fake.badDigestBody = "corrupted"
+	defer fake.Close()
+
+	var mu sync.Mutex
+	var handled []string
+	consumer := sqs.NewConsumer(fake.queue(), func(ctx context.Context, m *sqs.Message) error {
+		mu.Lock()
+		handled = append(handled, m.Body)
+		mu.Unlock()
+		return nil
+	})
+	consumer.Errors = make(chan *sqs.ConsumerError, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- consumer.Run(ctx) }()
+	c.Assert(waitFor(func() bool { return len(fake.deletedHandles()) == 2 }), Equals, true)
+	cancel()
+	c.Assert(<-done, IsNil)
+
+	sort.Strings(handled)
+	c.Assert(handled, DeepEquals, []string{"one", "two"})
+	err := <-consumer.Errors
+	c.Assert(err.Op, Equals, "receive")
+	c.Assert(err.Message.Body, Equals, "corrupted")
+	c.Assert(sqs.IsChecksumError(err), Equals, true)
+	c.Assert(err, ErrorMatches, "sqs: receive of message msg-2 failed: sqs: MD5 of body .*")
+}
+
+func (s *S) TestConsumerNeedsHandler(c *C) {
+	err := sqs.NewConsumer(s.queue(), nil).Run(context.Background())
+	c.Assert(err, ErrorMatches, "sqs: consumer needs a queue and a handler")
+}
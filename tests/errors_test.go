@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	. "launchpad.net/gocheck"
+	"net"
+	"net/url"
+	"os"
+	"sdk/sqs/sqs"
+	"syscall"
+)
+
+func (s *S) TestErrorIs(c *C) {
+	testServer.PrepareResponse(400, nil, TestNonExistentQueueXml)
+
+	_, err := s.sqs.GetQueueUrl("nonExistentQueue")
+	testServer.WaitRequest()
+
+	c.Assert(errors.Is(err, sqs.ErrNonExistentQueue), Equals, true)
+	c.Assert(errors.Is(err, sqs.ErrThrottling), Equals, false)
+	c.Assert(sqs.IsNotFound(err), Equals, true)
+	c.Assert(sqs.IsRetryable(err), Equals, false)
+	c.Assert(err, ErrorMatches, `The specified queue does not exist for this wsdl version\. \(AWS\.SimpleQueueService\.NonExistentQueue\)`)
+
+	var sqsErr *sqs.Error
+	c.Assert(errors.As(fmt.Errorf("wrapped: %w", err), &sqsErr), Equals, true)
+	c.Assert(sqsErr.RequestId, Equals, "05c9f47a-7c5a-5d4f-9d7e-3a3b2c6a9b4e")
+}
+
+func (s *S) TestErrorChain(c *C) {
+	client := sqs.New(s.sqs.Auth, s.sqs.Region)
+	client.Retry = sqs.NoRetry
+	testServer.PrepareResponse(400, nil, TestMultipleErrorsXml)
+
+	_, err := client.ListQueues()
+	testServer.WaitRequest()
+
+	sqsErr := err.(*sqs.Error)
+	all := sqsErr.All()
+	c.Assert(all, HasLen, 2)
+	c.Assert(all[0].Code, Equals, "InvalidParameterValue")
+	c.Assert(all[1].Code, Equals, "RequestThrottled")
+	c.Assert(all[1].RequestId, Equals, "c3f4b0e5-3bd1-4ec2-9ed0-96e9e3a1f6e2")
+	c.Assert(all[1].StatusCode, Equals, 400)
+	c.Assert(errors.Is(err, sqs.ErrInvalidParameterValue), Equals, true)
+	c.Assert(errors.Is(err, sqs.ErrThrottling), Equals, true)
+	c.Assert(sqs.IsThrottling(err), Equals, true)
+}
+
+func (s *S) TestIsRetryable(c *C) {
+	c.Assert(sqs.IsRetryable(nil), Equals, false)
+	c.Assert(sqs.IsRetryable(context.Canceled), Equals, false)
+	c.Assert(sqs.IsRetryable(&sqs.Error{StatusCode: 503, Code: "ServiceUnavailable"}), Equals, true)
+	c.Assert(sqs.IsRetryable(&sqs.Error{StatusCode: 400, Code: "ThrottlingException"}), Equals, true)
+	c.Assert(sqs.IsRetryable(&sqs.Error{StatusCode: 400, Code: "InvalidParameterValue"}), Equals, false)
+	c.Assert(sqs.IsRetryable(&sqs.Error{StatusCode: 403, Code: "RequestTimeTooSkewed"}), Equals, false)
+
+	transport := func(err error) error {
+		return &url.Error{Op: "Post", URL: "https://sqs.us-east-1.amazonaws.com/", Err: err}
+	}
+	c.Assert(sqs.IsRetryable(transport(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)})), Equals, true)
+	c.Assert(sqs.IsRetryable(transport(&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)})), Equals, true)
+	c.Assert(sqs.IsRetryable(transport(&net.DNSError{Err: "timeout", IsTimeout: true})), Equals, true)
+	c.Assert(sqs.IsRetryable(transport(io.EOF)), Equals, true)
+	c.Assert(sqs.IsRetryable(transport(x509.UnknownAuthorityError{})), Equals, false)
+	c.Assert(sqs.IsRetryable(transport(errors.New("unsupported protocol scheme \"ftp\""))), Equals, false)
+	// A response cut short was already acted upon by SQS.
+	c.Assert(sqs.IsRetryable(io.ErrUnexpectedEOF), Equals, false)
+}
+
+func (s *S) TestErrorMessageWithoutCode(c *C) {
+	err := &sqs.Error{StatusCode: 500, Message: "500 Internal Server Error"}
+	c.Assert(err, ErrorMatches, `500 Internal Server Error`)
+}
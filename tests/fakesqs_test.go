@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"launchpad.net/goamz/aws"
+	"net/http"
+	"net/http/httptest"
+	"sdk/sqs/sqs"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeSQS is a minimal in-memory queue service, answering requests by
+// action rather than in order, for tests of the concurrent helpers.
+type fakeSQS struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	pending       []string       // Bodies waiting to be received
+	received      int            // Messages handed out so far
+	deleted       []string       // Receipt handles of deleted messages
+	visibility    map[string]int // Last visibility timeout set per receipt handle
+	sent          []string       // Bodies of messages sent in batches
+	batches       int            // SendMessageBatch requests
+	failReceives  int            // ReceiveMessage calls left to fail
+	failSendBody  string         // Batch entries with this body fail
+	badDigestBody string         // Messages with this body get a wrong MD5
+	actionCounter map[string]int
+}
+
+func newFakeSQS(bodies ...string) *fakeSQS {
+	f := &fakeSQS{pending: bodies, visibility: map[string]int{}, actionCounter: map[string]int{}}
+	f.Server = httptest.NewServer(f)
+	return f
+}
+
+func (f *fakeSQS) queue() *sqs.Queue {
+	client := sqs.New(aws.Auth{AccessKey: "abc", SecretKey: "123"}, aws.Region{Name: "us-west-2", SQSEndpoint: f.URL})
+	return &sqs.Queue{SQS: client, Url: f.URL + "/123456789012/fakeQueue"}
+}
+
+func (f *fakeSQS) count(action string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.actionCounter[action]
+}
+
+func (f *fakeSQS) deletedHandles() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.deleted...)
+}
+
+func (f *fakeSQS) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	req.ParseForm()
+	action := req.Form.Get("Action")
+
+	f.mu.Lock()
+	f.actionCounter[action]++
+	var body string
+	switch action {
+	case "ReceiveMessage":
+		if f.failReceives > 0 {
+			f.failReceives--
+			f.mu.Unlock()
+			w.WriteHeader(503)
+			fmt.Fprint(w, TestServiceUnavailableXml)
+			return
+		}
+		body = f.receive(req)
+	case "DeleteMessage":
+		f.deleted = append(f.deleted, req.Form.Get("ReceiptHandle"))
+		body = `<DeleteMessageResponse><ResponseMetadata><RequestId>fake</RequestId></ResponseMetadata></DeleteMessageResponse>`
+	case "ChangeMessageVisibilityBatch":
+		body = f.changeVisibility(req)
+	case "SendMessageBatch":
+		body = f.sendBatch(req)
+	default:
+		f.mu.Unlock()
+		w.WriteHeader(400)
+		fmt.Fprint(w, TestInvalidParameterValueXml)
+		return
+	}
+	f.mu.Unlock()
+	fmt.Fprint(w, body)
+}
+
+func (f *fakeSQS) receive(req *http.Request) string {
+	max, _ := strconv.Atoi(req.Form.Get("MaxNumberOfMessages"))
+	if max == 0 {
+		max = 1
+	}
+	if len(f.pending) == 0 {
+		// Stand in for a short long poll.
+		f.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		f.mu.Lock()
+	}
+	var b strings.Builder
+	b.WriteString("<ReceiveMessageResponse><ReceiveMessageResult>")
+	for ; max > 0 && len(f.pending) > 0; max-- {
+		f.received++
+		body := f.pending[0]
+		f.pending = f.pending[1:]
+		digest := md5Hex(body)
+		if f.badDigestBody != "" && body == f.badDigestBody {
+			digest = md5Hex("corrupted " + body)
+		}
+		fmt.Fprintf(&b, "<Message><MessageId>msg-%d</MessageId><ReceiptHandle>handle-%d</ReceiptHandle><MD5OfBody>%s</MD5OfBody><Body>%s</Body></Message>",
+			f.received, f.received, digest, body)
+	}
+	b.WriteString("</ReceiveMessageResult><ResponseMetadata><RequestId>fake</RequestId></ResponseMetadata></ReceiveMessageResponse>")
+	return b.String()
+}
+
+func (f *fakeSQS) changeVisibility(req *http.Request) string {
+	var b strings.Builder
+	b.WriteString("<ChangeMessageVisibilityBatchResponse><ChangeMessageVisibilityBatchResult>")
+	for i := 1; ; i++ {
+		prefix := "ChangeMessageVisibilityBatchRequestEntry." + strconv.Itoa(i) + "."
+		id := req.Form.Get(prefix + "Id")
+		if id == "" {
+			break
+		}
+		timeout, _ := strconv.Atoi(req.Form.Get(prefix + "VisibilityTimeout"))
+		f.visibility[req.Form.Get(prefix+"ReceiptHandle")] = timeout
+		fmt.Fprintf(&b, "<ChangeMessageVisibilityBatchResultEntry><Id>%s</Id></ChangeMessageVisibilityBatchResultEntry>", id)
+	}
+	b.WriteString("</ChangeMessageVisibilityBatchResult><ResponseMetadata><RequestId>fake</RequestId></ResponseMetadata></ChangeMessageVisibilityBatchResponse>")
+	return b.String()
+}
+
+func (f *fakeSQS) sendBatch(req *http.Request) string {
+	f.batches++
+	var b strings.Builder
+	b.WriteString("<SendMessageBatchResponse><SendMessageBatchResult>")
+	for i := 1; ; i++ {
+		prefix := "SendMessageBatchRequestEntry." + strconv.Itoa(i) + "."
+		id := req.Form.Get(prefix + "Id")
+		if id == "" {
+			break
+		}
+		body := req.Form.Get(prefix + "MessageBody")
+		if f.failSendBody != "" && body == f.failSendBody {
+			fmt.Fprintf(&b, "<BatchResultErrorEntry><Id>%s</Id><Code>InvalidMessageContents</Code><Message>Invalid message contents</Message><SenderFault>true</SenderFault></BatchResultErrorEntry>", id)
+			continue
+		}
+		f.sent = append(f.sent, body)
+		digest := md5Hex(body)
+		if f.badDigestBody != "" && body == f.badDigestBody {
+			digest = md5Hex("corrupted " + body)
+		}
+		fmt.Fprintf(&b, "<SendMessageBatchResultEntry><Id>%s</Id><MessageId>sent-%d</MessageId><MD5OfMessageBody>%s</MD5OfMessageBody></SendMessageBatchResultEntry>",
+			id, len(f.sent), digest)
+	}
+	b.WriteString("</SendMessageBatchResult><ResponseMetadata><RequestId>fake</RequestId></ResponseMetadata></SendMessageBatchResponse>")
+	return b.String()
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
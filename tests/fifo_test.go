@@ -0,0 +1,84 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+)
+
+func (s *S) fifoQueue() *sqs.Queue {
+	return &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue.fifo"}
+}
+
+func (s *S) TestSendMessageFifo(c *C) {
+	testServer.PrepareResponse(200, nil, TestSendMessageFifoXmlOK)
+
+	q := s.fifoQueue()
+	resp, err := q.SendMessageWithParams(sqs.SendMessageParams{
+		MessageBody:            "This is a test message",
+		MessageGroupId:         "orders",
+		MessageDeduplicationId: sqs.ContentBasedDeduplicationId("This is a test message"),
+	})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(q.IsFifo(), Equals, true)
+	c.Assert(resp.SequenceNumber, Equals, "18849496460467696128")
+	c.Assert(req.Form["MessageGroupId"], DeepEquals, []string{"orders"})
+	c.Assert(req.Form["MessageDeduplicationId"], DeepEquals, []string{sqs.ContentBasedDeduplicationId("This is a test message")})
+}
+
+func (s *S) TestSendMessageFifoRequiresGroup(c *C) {
+	_, err := s.fifoQueue().SendMessage("This is a test message")
+	c.Assert(err, ErrorMatches, "sqs: a MessageGroupId is required to send to FIFO queue .*")
+
+	_, err = s.fifoQueue().SendMessageBatch([]sqs.SendMessageBatchRequestEntry{{Id: "1", MessageBody: "hello"}})
+	c.Assert(err, ErrorMatches, "sqs: a MessageGroupId is required to send to FIFO queue .*")
+}
+
+func (s *S) TestSendMessageFifoRejectsDelay(c *C) {
+	_, err := s.fifoQueue().SendMessageWithDelay("This is a test message", 10)
+	c.Assert(err, ErrorMatches, "sqs: a MessageGroupId is required to send to FIFO queue .*")
+
+	_, err = s.fifoQueue().SendMessageWithParams(sqs.SendMessageParams{MessageBody: "hello", MessageGroupId: "a", DelaySeconds: 10})
+	c.Assert(err, ErrorMatches, "sqs: FIFO queue .* does not support per-message DelaySeconds")
+
+	_, err = s.fifoQueue().SendMessageBatch([]sqs.SendMessageBatchRequestEntry{{Id: "1", MessageBody: "hello", MessageGroupId: "a", DelaySeconds: 10}})
+	c.Assert(err, ErrorMatches, "sqs: FIFO queue .* does not support per-message DelaySeconds")
+}
+
+func (s *S) TestSendMessageBatchFifo(c *C) {
+	testServer.PrepareResponse(200, nil, TestSendMessageBatchXmlOK)
+
+	_, err := s.fifoQueue().SendMessageBatch([]sqs.SendMessageBatchRequestEntry{
+		{Id: "test_msg_001", MessageBody: "test message body 1", MessageGroupId: "a", MessageDeduplicationId: "d1"},
+		{Id: "test_msg_002", MessageBody: "test message body 2", MessageGroupId: "b", MessageDeduplicationId: "d2"},
+	})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["SendMessageBatchRequestEntry.1.MessageGroupId"], DeepEquals, []string{"a"})
+	c.Assert(req.Form["SendMessageBatchRequestEntry.2.MessageDeduplicationId"], DeepEquals, []string{"d2"})
+	c.Assert(req.Form["SendMessageBatchRequestEntry.1.DelaySeconds"], IsNil)
+}
+
+func (s *S) TestReceiveMessageAttemptId(c *C) {
+	testServer.PrepareResponse(200, nil, TestReceiveMessageXmlOK)
+
+	_, err := s.fifoQueue().ReceiveMessageWithParams(sqs.ReceiveMessageParams{ReceiveRequestAttemptId: "attempt-1"})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["ReceiveRequestAttemptId"], DeepEquals, []string{"attempt-1"})
+}
+
+func (s *S) TestCreateFifoQueueName(c *C) {
+	_, err := s.sqs.CreateQueue("testQueue", []sqs.Attribute{{Name: "FifoQueue", Value: "true"}})
+	c.Assert(err, ErrorMatches, `sqs: FIFO queue name "testQueue" must end in ".fifo"`)
+
+	_, err = s.sqs.CreateQueue("testQueue.fifo", nil)
+	c.Assert(err, ErrorMatches, `sqs: queue name "testQueue.fifo" ends in ".fifo" but FifoQueue is not set`)
+}
+
+func (s *S) TestContentBasedDeduplicationId(c *C) {
+	c.Assert(sqs.ContentBasedDeduplicationId("hello"), Equals, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"strconv"
+	"time"
+)
+
+func (s *S) TestHeartbeatBeat(c *C) {
+	fake := newFakeSQS()
+	defer fake.Close()
+
+	h := sqs.NewHeartbeat(fake.queue(), 30*time.Second)
+	for i := 0; i < 12; i++ {
+		h.Track("handle-" + strconv.Itoa(i))
+	}
+	release := h.Track("released")
+	release()
+	c.Assert(h.Len(), Equals, 12)
+
+	h.Beat(context.Background())
+
+	c.Assert(fake.count("ChangeMessageVisibilityBatch"), Equals, 2)
+	c.Assert(fake.visibility, HasLen, 12)
+	c.Assert(fake.visibility["handle-0"], Equals, 30)
+	c.Assert(fake.visibility["handle-11"], Equals, 30)
+}
+
+func (s *S) TestHeartbeatMaxLease(c *C) {
+	fake := newFakeSQS()
+	defer fake.Close()
+
+	h := sqs.NewHeartbeat(fake.queue(), 30*time.Second)
+	h.MaxLease = 1500 * time.Millisecond
+	h.Track("short")
+	h.Beat(context.Background())
+	c.Assert(fake.visibility["short"], Equals, 2)
+
+	h.MaxLease = time.Millisecond
+	h.Track("expired")
+	time.Sleep(5 * time.Millisecond)
+	h.Release("short")
+	h.Beat(context.Background())
+	c.Assert(h.Len(), Equals, 0)
+	c.Assert(fake.count("ChangeMessageVisibilityBatch"), Equals, 1)
+}
+
+func (s *S) TestConsumerHeartbeat(c *C) {
+	fake := newFakeSQS("slow")
+	defer fake.Close()
+
+	q := fake.queue()
+	handled := make(chan bool, 1)
+	consumer := sqs.NewConsumer(q, func(ctx context.Context, m *sqs.Message) error {
+		time.Sleep(60 * time.Millisecond)
+		handled <- true
+		return nil
+	})
+	consumer.Heartbeat = sqs.NewHeartbeat(q, 30*time.Second)
+	consumer.Heartbeat.Interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- consumer.Run(ctx) }()
+	<-handled
+	c.Assert(waitFor(func() bool { return len(fake.deletedHandles()) == 1 }), Equals, true)
+	cancel()
+	c.Assert(<-done, IsNil)
+
+	c.Assert(fake.count("ChangeMessageVisibilityBatch") > 0, Equals, true)
+	c.Assert(consumer.Heartbeat.Len(), Equals, 0)
+
+	consumer.Heartbeat.Interval = 0
+	c.Assert(consumer.Run(context.Background()), ErrorMatches, "sqs: heartbeat needs .*")
+}
+
+func (s *S) TestConsumerHeartbeatWaitingMessages(c *C) {
+	fake := newFakeSQS("first", "second")
+	defer fake.Close()
+
+	q := fake.queue()
+	unblock := make(chan bool)
+	consumer := sqs.NewConsumer(q, func(ctx context.Context, m *sqs.Message) error {
+		<-unblock
+		return nil
+	})
+	consumer.Workers = 1
+	consumer.Heartbeat = sqs.NewHeartbeat(q, 30*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- consumer.Run(ctx) }()
+	// The second message waits for the only worker, but is tracked already.
+	c.Assert(waitFor(func() bool { return consumer.Heartbeat.Len() == 2 }), Equals, true)
+	cancel()
+	c.Assert(waitFor(func() bool { return consumer.Heartbeat.Len() == 1 }), Equals, true)
+	close(unblock)
+	c.Assert(<-done, IsNil)
+
+	c.Assert(consumer.Heartbeat.Len(), Equals, 0)
+	c.Assert(fake.deletedHandles(), DeepEquals, []string{"handle-1"})
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+func (s *S) TestListQueuesPage(c *C) {
+	testServer.PrepareResponse(200, nil, TestListQueuesPage1XmlOK)
+
+	resp, err := s.sqs.ListQueuesPage("queue", 2, "")
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"ListQueues"})
+	c.Assert(req.Form["QueueNamePrefix"], DeepEquals, []string{"queue"})
+	c.Assert(req.Form["MaxResults"], DeepEquals, []string{"2"})
+	c.Assert(req.Form["NextToken"], IsNil)
+	c.Assert(err, IsNil)
+	c.Assert(resp.QueueUrl, HasLen, 2)
+	c.Assert(resp.NextToken, Equals, "AQICAHhNZXh0VG9rZW4tcGFnZTI=")
+}
+
+func (s *S) TestListQueuesFollowsPages(c *C) {
+	testServer.PrepareResponse(200, nil, TestListQueuesPage1XmlOK)
+	testServer.PrepareResponse(200, nil, TestListQueuesPage2XmlOK)
+
+	resp, err := s.sqs.ListQueuesWithPrefix("queue")
+	first := testServer.WaitRequest()
+	second := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.QueueUrl, HasLen, 3)
+	c.Assert(resp.NextToken, Equals, "")
+	c.Assert(first.Form["MaxResults"], DeepEquals, []string{"1000"})
+	c.Assert(second.Form["QueueNamePrefix"], DeepEquals, []string{"queue"})
+	c.Assert(second.Form["NextToken"], DeepEquals, []string{"AQICAHhNZXh0VG9rZW4tcGFnZTI="})
+}
+
+func (s *S) TestQueueIterator(c *C) {
+	testServer.PrepareResponse(200, nil, TestListQueuesPage1XmlOK)
+	testServer.PrepareResponse(200, nil, TestListQueuesPage2XmlOK)
+
+	var urls []string
+	it := s.sqs.Queues("")
+	for it.Next() {
+		urls = append(urls, it.Queue().Url)
+	}
+	first := testServer.WaitRequest()
+	second := testServer.WaitRequest()
+
+	c.Assert(it.Err(), IsNil)
+	c.Assert(urls, DeepEquals, []string{
+		"http://sqs.us-east-1.amazonaws.com/123456789012/queue1",
+		"http://sqs.us-east-1.amazonaws.com/123456789012/queue2",
+		"http://sqs.us-east-1.amazonaws.com/123456789012/queue3",
+	})
+	c.Assert(first.Form["MaxResults"], DeepEquals, []string{"1000"})
+	c.Assert(first.Form["NextToken"], IsNil)
+	c.Assert(second.Form["NextToken"], DeepEquals, []string{"AQICAHhNZXh0VG9rZW4tcGFnZTI="})
+	c.Assert(it.Next(), Equals, false)
+}
+
+func (s *S) TestQueueIteratorError(c *C) {
+	testServer.PrepareResponse(400, nil, TestInvalidParameterValueXml)
+
+	it := s.sqs.Queues("").PageSize(5000)
+	c.Assert(it.Next(), Equals, false)
+	testServer.WaitRequest()
+
+	c.Assert(it.Err(), NotNil)
+	c.Assert(it.Queue(), IsNil)
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"fmt"
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+)
+
+var testMessageAttributes = map[string]sqs.MessageAttributeValue{
+	"trace-id": sqs.StringAttribute("1-5759e988-bd862e3fe1be46a994272793").WithCustomType("xray"),
+	"priority": sqs.IntAttribute(3),
+	"payload":  sqs.BinaryAttribute([]byte{0xca, 0xfe}),
+}
+
+func (s *S) TestSendMessageWithAttributes(c *C) {
+	testServer.PrepareResponse(200, nil, fmt.Sprintf(TestSendMessageWithAttributesXmlOK, sqs.MessageAttributesMD5(testMessageAttributes)))
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	resp, err := q.SendMessageWithParams(sqs.SendMessageParams{
+		MessageBody:       "This is a test message",
+		MessageAttributes: testMessageAttributes,
+	})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.MessageId, Equals, "5fea7756-0ea4-451a-a703-a558b933e274")
+	c.Assert(req.Form["MessageAttribute.1.Name"], DeepEquals, []string{"payload"})
+	c.Assert(req.Form["MessageAttribute.1.Value.DataType"], DeepEquals, []string{"Binary"})
+	c.Assert(req.Form["MessageAttribute.1.Value.BinaryValue"], DeepEquals, []string{"yv4="})
+	c.Assert(req.Form["MessageAttribute.2.Name"], DeepEquals, []string{"priority"})
+	c.Assert(req.Form["MessageAttribute.2.Value.DataType"], DeepEquals, []string{"Number"})
+	c.Assert(req.Form["MessageAttribute.2.Value.StringValue"], DeepEquals, []string{"3"})
+	c.Assert(req.Form["MessageAttribute.3.Name"], DeepEquals, []string{"trace-id"})
+	c.Assert(req.Form["MessageAttribute.3.Value.DataType"], DeepEquals, []string{"String.xray"})
+	c.Assert(req.Form["DelaySeconds"], IsNil)
+}
+
+func (s *S) TestSendMessageWithAttributesBadDigest(c *C) {
+	testServer.PrepareResponse(200, nil, fmt.Sprintf(TestSendMessageWithAttributesXmlOK, "0123456789abcdef0123456789abcdef"))
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	_, err := q.SendMessageWithParams(sqs.SendMessageParams{
+		MessageBody:       "This is a test message",
+		MessageAttributes: testMessageAttributes,
+	})
+	testServer.WaitRequest()
+
+	c.Assert(err, ErrorMatches, "sqs: MD5 of message attributes of message .* is .*, expected .*")
+	c.Assert(sqs.IsChecksumError(err), Equals, true)
+}
+
+func (s *S) TestReceiveMessageAttributes(c *C) {
+	attrs := map[string]sqs.MessageAttributeValue{
+		"color":   sqs.StringAttribute("blue"),
+		"payload": sqs.BinaryAttribute([]byte("hello")),
+	}
+	testServer.PrepareResponse(200, nil, fmt.Sprintf(TestReceiveMessageWithAttributesXmlOK, sqs.MessageAttributesMD5(attrs)))
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	resp, err := q.ReceiveMessageWithParams(sqs.ReceiveMessageParams{MessageAttributeNames: []string{"All"}})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["MessageAttributeName.1"], DeepEquals, []string{"All"})
+	c.Assert(resp.Messages, HasLen, 1)
+	c.Assert(resp.Messages[0].MessageAttributes(), DeepEquals, attrs)
+}
+
+func (s *S) TestMessageAttributeValueTypes(c *C) {
+	v := sqs.NumberAttribute("3.14").WithCustomType("float")
+	c.Assert(v.DataType, Equals, "Number.float")
+	c.Assert(v.BaseType(), Equals, "Number")
+	c.Assert(v.CustomType(), Equals, "float")
+	c.Assert(sqs.MessageAttributesMD5(nil), Equals, "")
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"fmt"
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"time"
+)
+
+func (s *S) TestStartMessageMoveTask(c *C) {
+	testServer.PrepareResponse(200, nil, TestStartMessageMoveTaskXmlOK)
+
+	resp, err := s.sqs.StartMessageMoveTask("arn:aws:sqs:us-east-1:123456789012:testQueue-dlq", "", 50)
+	req := testServer.WaitRequest()
+
+	c.Assert(req.URL.Path, Equals, "/")
+	c.Assert(req.Form["Action"], DeepEquals, []string{"StartMessageMoveTask"})
+	c.Assert(req.Form["SourceArn"], DeepEquals, []string{"arn:aws:sqs:us-east-1:123456789012:testQueue-dlq"})
+	c.Assert(req.Form["DestinationArn"], IsNil)
+	c.Assert(req.Form["MaxNumberOfMessagesPerSecond"], DeepEquals, []string{"50"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.TaskHandle, Matches, "eyJ0.*")
+}
+
+func (s *S) TestListMessageMoveTasks(c *C) {
+	testServer.PrepareResponse(200, nil, TestListMessageMoveTasksXmlOK)
+
+	resp, err := s.sqs.ListMessageMoveTasks("arn:aws:sqs:us-east-1:123456789012:testQueue-dlq", 10)
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"ListMessageMoveTasks"})
+	c.Assert(req.Form["MaxResults"], DeepEquals, []string{"10"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.Tasks, HasLen, 2)
+
+	running := resp.Tasks[0]
+	c.Assert(running.Status, Equals, sqs.MessageMoveTaskRunning)
+	c.Assert(running.Status.Done(), Equals, false)
+	c.Assert(running.MaxNumberOfMessagesPerSecond, Equals, 50)
+	c.Assert(running.ApproximateNumberOfMessagesMoved, Equals, int64(203))
+	c.Assert(running.ApproximateNumberOfMessagesToMove, Equals, int64(30))
+	c.Assert(running.Started().Equal(time.Unix(1442428276, 921000000)), Equals, true)
+
+	failed := resp.Tasks[1]
+	c.Assert(failed.Status, Equals, sqs.MessageMoveTaskFailed)
+	c.Assert(failed.Status.Done(), Equals, true)
+	c.Assert(failed.TaskHandle, Equals, "")
+	c.Assert(failed.DestinationArn, Equals, "arn:aws:sqs:us-east-1:123456789012:otherQueue")
+	c.Assert(failed.FailureReason, Equals, "AWS.SimpleQueueService.NonExistentQueue")
+}
+
+func (s *S) TestCancelMessageMoveTask(c *C) {
+	testServer.PrepareResponse(200, nil, TestCancelMessageMoveTaskXmlOK)
+
+	resp, err := s.sqs.CancelMessageMoveTask("handle")
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"CancelMessageMoveTask"})
+	c.Assert(req.Form["TaskHandle"], DeepEquals, []string{"handle"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.ApproximateNumberOfMessagesMoved, Equals, int64(203))
+}
+
+func (s *S) TestQueueRedrive(c *C) {
+	testServer.PrepareResponse(200, nil, fmt.Sprintf(TestGetQueueAttributesQueueArnXmlOK, "arn:aws:sqs:us-east-1:123456789012:testQueue"))
+	testServer.PrepareResponse(200, nil, fmt.Sprintf(TestGetQueueAttributesQueueArnXmlOK, "arn:aws:sqs:us-east-1:123456789012:otherQueue"))
+	testServer.PrepareResponse(200, nil, TestStartMessageMoveTaskXmlOK)
+
+	destination := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/otherQueue"}
+	_, err := s.queue().Redrive(destination, 0)
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Form["AttributeName.1"], DeepEquals, []string{"QueueArn"})
+	c.Assert(req.URL.Path, Equals, "/123456789012/testQueue")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/123456789012/otherQueue")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/")
+	c.Assert(req.Form["SourceArn"], DeepEquals, []string{"arn:aws:sqs:us-east-1:123456789012:testQueue"})
+	c.Assert(req.Form["DestinationArn"], DeepEquals, []string{"arn:aws:sqs:us-east-1:123456789012:otherQueue"})
+	c.Assert(req.Form["MaxNumberOfMessagesPerSecond"], IsNil)
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+)
+
+const testTopicArn = "arn:aws:sns:us-east-1:123456789012:testTopic"
+
+func testPolicy() *sqs.Policy {
+	p := sqs.NewPolicy("/123456789012/testQueue/SQSDefaultPolicy")
+	p.Allow("TopicSend", sqs.ServicePrincipal("sns.amazonaws.com"), "arn:aws:sqs:us-east-1:123456789012:testQueue", "sqs:SendMessage").
+		WhenSourceArn(testTopicArn)
+	p.Allow("AccountReceive", sqs.AWSPrincipal("111122223333"), "arn:aws:sqs:us-east-1:123456789012:testQueue", "sqs:ReceiveMessage", "sqs:DeleteMessage")
+	return p
+}
+
+func (s *S) TestPolicyAttribute(c *C) {
+	attribute, err := testPolicy().Attribute()
+	c.Assert(err, IsNil)
+	c.Assert(attribute.Name, Equals, "Policy")
+	c.Assert(attribute.Value, Equals, `{"Version":"2012-10-17","Id":"/123456789012/testQueue/SQSDefaultPolicy","Statement":[`+
+		`{"Sid":"TopicSend","Effect":"Allow","Principal":{"Service":"sns.amazonaws.com"},"Action":"sqs:SendMessage",`+
+		`"Resource":"arn:aws:sqs:us-east-1:123456789012:testQueue","Condition":{"ArnEquals":{"aws:SourceArn":"arn:aws:sns:us-east-1:123456789012:testTopic"}}},`+
+		`{"Sid":"AccountReceive","Effect":"Allow","Principal":{"AWS":"111122223333"},"Action":["sqs:ReceiveMessage","sqs:DeleteMessage"],`+
+		`"Resource":"arn:aws:sqs:us-east-1:123456789012:testQueue"}]}`)
+
+	parsed, err := sqs.ParsePolicy(attribute.Value)
+	c.Assert(err, IsNil)
+	c.Assert(parsed, DeepEquals, testPolicy())
+
+	_, err = sqs.NewPolicy("").Attribute()
+	c.Assert(err, ErrorMatches, "sqs: queue policy needs at least one statement")
+	p := sqs.NewPolicy("")
+	p.Allow("NoActions", sqs.AnyPrincipal(), "")
+	_, err = p.Attribute()
+	c.Assert(err, ErrorMatches, "sqs: statement 1 of queue policy has no action")
+}
+
+func (s *S) TestParsePolicy(c *C) {
+	p, err := sqs.ParsePolicy(`{
+		"Version": "2008-10-17",
+		"Statement": [{
+			"Sid": "Queue1ReceiveMessage",
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": "SQS:ReceiveMessage",
+			"Resource": ["arn:aws:sqs:us-east-1:123456789012:testQueue"],
+			"Condition": {"StringEquals": {"aws:SourceAccount": ["123456789012", "111122223333"]}}
+		}]
+	}`)
+	c.Assert(err, IsNil)
+	c.Assert(p.Statement, HasLen, 1)
+	statement := p.Lookup("Queue1ReceiveMessage")
+	c.Assert(statement.Principal.Anyone, Equals, true)
+	c.Assert(statement.Action, DeepEquals, sqs.StringList{"SQS:ReceiveMessage"})
+	c.Assert(statement.Condition["StringEquals"][sqs.ConditionSourceAccount], DeepEquals, sqs.ConditionValues{"123456789012", "111122223333"})
+
+	_, err = sqs.ParsePolicy(`{"Statement":[{"Effect":"Allow","Principal":"someone"}]}`)
+	c.Assert(err, ErrorMatches, `sqs: invalid queue policy: invalid principal "someone"`)
+}
+
+const testDenyInsecurePolicy = `{"Version":"2012-10-17","Statement":[` +
+	`{"Sid":"DenyInsecure","Effect":"Deny","Principal":"*","NotAction":"sqs:GetQueueUrl","Resource":"arn:aws:sqs:us-east-1:123456789012:testQueue",` +
+	`"Condition":{"Bool":{"aws:SecureTransport":false},"NumericLessThan":{"s3:max-keys":10}}},` +
+	`{"Effect":"Allow","NotPrincipal":{"AWS":"111122223333","CanonicalUser":"79a59df900b949e5","Federated":"cognito-identity.amazonaws.com","Custom":"x"},` +
+	`"Action":"sqs:SendMessage","NotResource":["arn:aws:sqs:us-east-1:123456789012:a","arn:aws:sqs:us-east-1:123456789012:b"]}]}`
+
+func (s *S) TestPolicyRoundTrip(c *C) {
+	p, err := sqs.ParsePolicy(testDenyInsecurePolicy)
+	c.Assert(err, IsNil)
+	deny := p.Lookup("DenyInsecure")
+	c.Assert(deny.Condition["Bool"]["aws:SecureTransport"], DeepEquals, sqs.ConditionValues{false})
+	c.Assert(deny.NotAction, DeepEquals, sqs.StringList{"sqs:GetQueueUrl"})
+	allow := p.Statement[1]
+	c.Assert(allow.Principal, IsNil)
+	c.Assert(allow.NotPrincipal.CanonicalUser, DeepEquals, sqs.StringList{"79a59df900b949e5"})
+	c.Assert(allow.NotPrincipal.Federated, DeepEquals, sqs.StringList{"cognito-identity.amazonaws.com"})
+	c.Assert(allow.NotPrincipal.Other["Custom"], DeepEquals, sqs.StringList{"x"})
+	c.Assert(allow.NotResource, HasLen, 2)
+
+	attribute, err := p.Attribute()
+	c.Assert(err, IsNil)
+	c.Assert(attribute.Value, Equals, `{"Version":"2012-10-17","Statement":[`+
+		`{"Sid":"DenyInsecure","Effect":"Deny","Principal":"*","NotAction":"sqs:GetQueueUrl","Resource":"arn:aws:sqs:us-east-1:123456789012:testQueue",`+
+		`"Condition":{"Bool":{"aws:SecureTransport":false},"NumericLessThan":{"s3:max-keys":10}}},`+
+		`{"Effect":"Allow","NotPrincipal":{"AWS":"111122223333","CanonicalUser":"79a59df900b949e5","Custom":"x","Federated":"cognito-identity.amazonaws.com"},`+
+		`"Action":"sqs:SendMessage","NotResource":["arn:aws:sqs:us-east-1:123456789012:a","arn:aws:sqs:us-east-1:123456789012:b"]}]}`)
+	reparsed, err := sqs.ParsePolicy(attribute.Value)
+	c.Assert(err, IsNil)
+	c.Assert(sqs.DiffPolicies(p, reparsed).Empty(), Equals, true)
+
+	reparsed.Statement[1].NotResource = reparsed.Statement[1].NotResource[:1]
+	diff := sqs.DiffPolicies(p, reparsed)
+	c.Assert(diff.Removed, DeepEquals, []*sqs.Statement{allow})
+	c.Assert(diff.Added, DeepEquals, []*sqs.Statement{reparsed.Statement[1]})
+
+	single, err := sqs.ParsePolicy(`{"Statement":{"Effect":"Allow","Principal":"*","Action":"sqs:*"}}`)
+	c.Assert(err, IsNil)
+	c.Assert(single.Statement, HasLen, 1)
+}
+
+func (s *S) TestRawPolicyAttribute(c *C) {
+	testServer.PrepareResponse(200, nil, TestSetQueueAttributesXmlOK)
+
+	_, err := s.queue().SetQueueAttributes(sqs.Attribute{Name: "Policy", Value: testDenyInsecurePolicy})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Attribute.1.Value"], DeepEquals, []string{testDenyInsecurePolicy})
+}
+
+func (s *S) TestDiffPolicies(c *C) {
+	from := testPolicy()
+	to := testPolicy()
+	c.Assert(sqs.DiffPolicies(from, to).Empty(), Equals, true)
+
+	to.Remove("AccountReceive")
+	to.Lookup("TopicSend").When("ArnEquals", sqs.ConditionSourceArn, "arn:aws:sns:us-east-1:123456789012:otherTopic")
+	added := to.Allow("", sqs.AnyPrincipal(), "", "sqs:GetQueueAttributes")
+
+	diff := sqs.DiffPolicies(from, to)
+	c.Assert(diff.Added, DeepEquals, []*sqs.Statement{added})
+	c.Assert(diff.Removed, DeepEquals, []*sqs.Statement{from.Lookup("AccountReceive")})
+	c.Assert(diff.Changed, HasLen, 1)
+	c.Assert(diff.Changed[0].Old, Equals, from.Lookup("TopicSend"))
+	c.Assert(diff.Changed[0].New, Equals, to.Lookup("TopicSend"))
+
+	c.Assert(sqs.DiffPolicies(nil, to).Added, HasLen, 2)
+}
+
+func (s *S) TestSetPolicy(c *C) {
+	testServer.PrepareResponse(200, nil, TestSetQueueAttributesXmlOK)
+
+	_, err := s.queue().SetPolicy(testPolicy())
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Attribute.1.Name"], DeepEquals, []string{"Policy"})
+	parsed, err := sqs.ParsePolicy(req.Form["Attribute.1.Value"][0])
+	c.Assert(err, IsNil)
+	c.Assert(parsed, DeepEquals, testPolicy())
+
+	_, err = s.queue().SetQueueAttributes(sqs.Attribute{Name: "Policy", Value: "{not json"})
+	c.Assert(err, ErrorMatches, "sqs: invalid queue policy: .*")
+}
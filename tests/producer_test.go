@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func (s *S) TestProducerCoalescesConcurrentSends(c *C) {
+	fake := newFakeSQS()
+	defer fake.Close()
+	producer := sqs.NewProducer(fake.queue())
+	producer.Linger = time.Hour
+
+	var wg sync.WaitGroup
+	ids := make([]string, 20)
+	errs := make([]error, 20)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			entry, err := producer.Send(context.Background(), sqs.SendMessageParams{MessageBody: "m" + strconv.Itoa(i)})
+			errs[i] = err
+			if entry != nil {
+				ids[i] = entry.MessageId
+			}
+		}(i)
+	}
+	// Full batches go out without waiting for Linger.
+	wg.Wait()
+	c.Assert(producer.Close(), IsNil)
+
+	c.Assert(fake.count("SendMessageBatch"), Equals, 2)
+	for i := range ids {
+		c.Assert(errs[i], IsNil)
+		c.Assert(strings.HasPrefix(ids[i], "sent-"), Equals, true)
+	}
+	sort.Strings(ids)
+	for i := 1; i < len(ids); i++ {
+		c.Assert(ids[i], Not(Equals), ids[i-1])
+	}
+
+	_, err := producer.Send(context.Background(), sqs.SendMessageParams{MessageBody: "late"})
+	c.Assert(err, Equals, sqs.ErrProducerClosed)
+}
+
+func (s *S) TestProducerLinger(c *C) {
+	fake := newFakeSQS()
+	defer fake.Close()
+	producer := sqs.NewProducer(fake.queue())
+	defer producer.Close()
+
+	entry, err := producer.Send(context.Background(), sqs.SendMessageParams{MessageBody: "alone"})
+	c.Assert(err, IsNil)
+	c.Assert(entry.MessageId, Equals, "sent-1")
+	c.Assert(fake.count("SendMessageBatch"), Equals, 1)
+}
+
+func (s *S) TestProducerMaxBatchBytes(c *C) {
+	fake := newFakeSQS()
+	defer fake.Close()
+	producer := sqs.NewProducer(fake.queue())
+	producer.Linger = time.Hour
+	producer.MaxBatchBytes = 10
+
+	var wg sync.WaitGroup
+	for _, body := range []string{"aaaaaa", "bbbbbb"} {
+		wg.Add(1)
+		go func(body string) {
+			defer wg.Done()
+			_, err := producer.Send(context.Background(), sqs.SendMessageParams{MessageBody: body})
+			c.Check(err, IsNil)
+		}(body)
+	}
+	// The second message does not fit next to the first one, which goes out
+	// alone; the second waits for Close.
+	c.Assert(waitFor(func() bool { return fake.count("SendMessageBatch") == 1 }), Equals, true)
+	producer.Close()
+	wg.Wait()
+	c.Assert(fake.count("SendMessageBatch"), Equals, 2)
+}
+
+func (s *S) TestProducerFailedEntry(c *C) {
+	fake := newFakeSQS()
+	fake.failSendBody = "bad"
+	defer fake.Close()
+	producer := sqs.NewProducer(fake.queue())
+	defer producer.Close()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, body := range []string{"good", "bad"} {
+		wg.Add(1)
+		go func(i int, body string) {
+			defer wg.Done()
+			_, errs[i] = producer.Send(context.Background(), sqs.SendMessageParams{MessageBody: body})
+		}(i, body)
+	}
+	wg.Wait()
+
+	c.Assert(errs[0], IsNil)
+	failed, ok := errs[1].(sqs.BatchResultErrorEntry)
+	c.Assert(ok, Equals, true)
+	c.Assert(failed.Code, Equals, "InvalidMessageContents")
+}
+
+func (s *S) TestProducerSendContextDone(c *C) {
+	fake := newFakeSQS()
+	defer fake.Close()
+	producer := sqs.NewProducer(fake.queue())
+	producer.Linger = time.Hour
+	defer producer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := producer.Send(ctx, sqs.SendMessageParams{MessageBody: "waiting"})
+	c.Assert(err, Equals, context.DeadlineExceeded)
+}
+
+func (s *S) TestProducerFifoNeedsGroupId(c *C) {
+	q := &sqs.Queue{SQS: nil, Url: "https://sqs.us-east-1.amazonaws.com/123456789012/orders.fifo"}
+	_, err := sqs.NewProducer(q).Send(context.Background(), sqs.SendMessageParams{MessageBody: "order"})
+	c.Assert(err, NotNil)
+}
+
+func (s *S) TestProducerFifoBatchOrder(c *C) {
+	fake := newFakeSQS()
+	defer fake.Close()
+	q := fake.queue()
+	q.Url += ".fifo"
+	producer := sqs.NewProducer(q)
+	producer.Linger = time.Hour
+
+	// A done context makes Send return once the message is buffered, so
+	// that the messages are queued in a known order.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var bodies []string
+	for i := 0; i < 50; i++ {
+		body := "m" + strconv.Itoa(i)
+		bodies = append(bodies, body)
+		_, err := producer.Send(ctx, sqs.SendMessageParams{MessageBody: body, MessageGroupId: "g"})
+		c.Assert(err, Equals, context.Canceled)
+	}
+	producer.Close()
+
+	c.Assert(fake.count("SendMessageBatch"), Equals, 5)
+	c.Assert(fake.sent, DeepEquals, bodies)
+}
+
+func (s *S) TestProducerChecksumMismatch(c *C) {
+	fake := newFakeSQS()
+	fake.badDigestBody = "corrupted"
+	defer fake.Close()
+	producer := sqs.NewProducer(fake.queue())
+	defer producer.Close()
+
+	var wg sync.WaitGroup
+	entries := make([]*sqs.SendMessageBatchResultEntry, 2)
+	errs := make([]error, 2)
+	for i, body := range []string{"intact", "corrupted"} {
+		wg.Add(1)
+		go func(i int, body string) {
+			defer wg.Done()
+			entries[i], errs[i] = producer.Send(context.Background(), sqs.SendMessageParams{MessageBody: body})
+		}(i, body)
+	}
+	wg.Wait()
+
+	c.Assert(errs[0], IsNil)
+	c.Assert(entries[0].MessageId, Not(Equals), "")
+	c.Assert(sqs.IsChecksumError(errs[1]), Equals, true)
+	c.Assert(errs[1].(*sqs.ChecksumError).Part, Equals, sqs.ChecksumBody)
+	c.Assert(entries[1].MessageId, Not(Equals), "")
+}
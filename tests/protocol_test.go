@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"time"
+)
+
+func (s *S) queue() *sqs.Queue {
+	return &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+}
+
+func (s *S) TestAPIVersion(c *C) {
+	testServer.PrepareResponse(200, nil, TestListQueuesXmlOK)
+
+	_, err := s.sqs.ListQueues()
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Version"], DeepEquals, []string{"2012-11-05"})
+}
+
+func (s *S) TestErrorResponse(c *C) {
+	testServer.PrepareResponse(400, nil, TestNonExistentQueueXml)
+
+	_, err := s.sqs.GetQueueUrl("nonExistentQueue")
+	testServer.WaitRequest()
+
+	sqsErr, ok := err.(*sqs.Error)
+	c.Assert(ok, Equals, true)
+	c.Assert(sqsErr.StatusCode, Equals, 400)
+	c.Assert(sqsErr.Type, Equals, "Sender")
+	c.Assert(sqsErr.Code, Equals, "AWS.SimpleQueueService.NonExistentQueue")
+	c.Assert(sqsErr.Message, Equals, "The specified queue does not exist for this wsdl version.")
+	c.Assert(sqsErr.RequestId, Equals, "05c9f47a-7c5a-5d4f-9d7e-3a3b2c6a9b4e")
+}
+
+func (s *S) TestLegacyErrorResponse(c *C) {
+	testServer.PrepareResponse(400, nil, TestInvalidParameterValueXml)
+
+	_, err := s.sqs.GetQueueUrl("quename_nonalpha")
+	testServer.WaitRequest()
+
+	sqsErr := err.(*sqs.Error)
+	c.Assert(sqsErr.Code, Equals, "InvalidParameterValue")
+	c.Assert(sqsErr.RequestId, Equals, "42d59b56-7407-4c4a-be0f-4c88daeea257")
+}
+
+func (s *S) TestPurge(c *C) {
+	testServer.PrepareResponse(200, nil, TestPurgeQueueXmlOK)
+
+	resp, err := s.queue().Purge()
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.URL.Path, Equals, "/123456789012/testQueue")
+	c.Assert(req.Form["Action"], DeepEquals, []string{"PurgeQueue"})
+	c.Assert(resp.RequestId, Equals, "6fde8d1e-52cd-4581-8cd9-c512f4c64223")
+}
+
+func (s *S) TestPurgeInProgress(c *C) {
+	testServer.PrepareResponse(403, nil, TestPurgeQueueInProgressXml)
+
+	_, err := s.queue().Purge()
+	testServer.WaitRequest()
+
+	c.Assert(sqs.IsPurgeInProgress(err), Equals, true)
+	c.Assert(sqs.IsRetryable(err), Equals, false)
+}
+
+func (s *S) TestPurgeAndWait(c *C) {
+	testServer.PrepareResponse(403, nil, TestPurgeQueueInProgressXml)
+	testServer.PrepareResponse(403, nil, TestPurgeQueueInProgressXml)
+	testServer.PrepareResponse(200, nil, TestPurgeQueueXmlOK)
+
+	resp, err := s.queue().PurgeAndWait(10 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		req := testServer.WaitRequest()
+		c.Assert(req.Form["Action"], DeepEquals, []string{"PurgeQueue"})
+	}
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.RequestId, Equals, "6fde8d1e-52cd-4581-8cd9-c512f4c64223")
+}
+
+func (s *S) TestPurgeAndWaitContextDone(c *C) {
+	testServer.PrepareResponse(403, nil, TestPurgeQueueInProgressXml)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := s.queue().PurgeAndWaitContext(ctx, time.Hour)
+	testServer.WaitRequest()
+
+	c.Assert(err, Equals, context.DeadlineExceeded)
+}
+
+func (s *S) TestListDeadLetterSourceQueues(c *C) {
+	testServer.PrepareResponse(200, nil, TestListDeadLetterSourceQueuesXmlOK)
+
+	resp, err := s.queue().ListDeadLetterSourceQueues()
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Action"], DeepEquals, []string{"ListDeadLetterSourceQueues"})
+	c.Assert(resp.QueueUrls, DeepEquals, []string{"https://sqs.us-east-1.amazonaws.com/123456789012/MySourceQueue"})
+}
+
+func (s *S) TestDeleteMessageBatch(c *C) {
+	testServer.PrepareResponse(200, nil, TestDeleteMessageBatchXmlOK)
+
+	resp, err := s.queue().DeleteMessageBatch([]sqs.DeleteMessageBatch{
+		{Id: "msg1", ReceiptHandle: "gfk0T0R0waama4fVFffkjPQrrvzMrOg0fTFk2LxT33EuB8wR0ZCFgKWyXGWFoqqpCIiprQUEhir%2F5LeGPpYTLzjqLQxyQYaQALeSNHb0us3uE84uujxpBhsDkZUQkjFFkNqBXn48xlMcVhTcI3YLH%2Bd%2BIqetIOHgBCZAPx6r%2B09dWaBXei6nbK5Ygih21DCDdAwFV68Jo8DXhb3ErEfoDqx7vyvC5nCpdwqv%2BJhU%2FTNGjNN8t51v5c%2FAXvQsAzyZVNapxUrHIt4NxRhKJ72uICcxruyE8eRXlxIVNgeNP8ZEDcw7zZU1Zw%3D%3D"},
+		{Id: "msg2", ReceiptHandle: "gfk0T0R0waama4fVFffkjKzmhMCymjQvfTFk2LxT33G4ms5subrE0deLKWSscPU1oD3J9zgeS4PQQ3U30qOumIE6AdAv3w%2F%2Fa1IXW6AqaWhGsEPaLm3Vf6IiWqdM8u5imB%2BNTwj3tQRzOWdTOePjOjPcTpRxBtXix%2BEvwJOZUma9wabv%2BSw6ZHjwmNcVDx8dZXJhVp16Bksiox%2FGrUvrVTCJRTWTLc59oHLLF8sEkKzRmGNzTDGTiV%2BYjHfQj60FD3rVaXmzTsoNxRhKJ72uIHVMGVQiAGgB%2BqAbSqfKHDQtVOmJJgkHug%3D%3D"},
+	})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["DeleteMessageBatchRequestEntry.2.Id"], DeepEquals, []string{"msg2"})
+	c.Assert(resp.Ids, DeepEquals, []string{"msg1", "msg2"})
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"strconv"
+	"time"
+)
+
+func (s *S) TestQueueAttributes(c *C) {
+	testServer.PrepareResponse(200, nil, TestGetQueueAttributesAllXmlOK)
+
+	attrs, err := s.queue().Attributes()
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"GetQueueAttributes"})
+	c.Assert(req.Form["AttributeName.1"], DeepEquals, []string{"All"})
+	c.Assert(err, IsNil)
+	c.Assert(attrs.QueueArn, Equals, "arn:aws:sqs:us-east-1:123456789012:testQueue")
+	c.Assert(attrs.ApproximateNumberOfMessages, Equals, int64(12))
+	c.Assert(attrs.ApproximateNumberOfMessagesNotVisible, Equals, int64(3))
+	c.Assert(attrs.CreatedTimestamp.Equal(time.Unix(1286771522, 0)), Equals, true)
+	c.Assert(attrs.LastModifiedTimestamp.Equal(time.Unix(1286771600, 0)), Equals, true)
+	c.Assert(attrs.VisibilityTimeout, Equals, 30*time.Second)
+	c.Assert(attrs.MaximumMessageSize, Equals, int64(262144))
+	c.Assert(attrs.MessageRetentionPeriod, Equals, 4*24*time.Hour)
+	c.Assert(attrs.ReceiveMessageWaitTime, Equals, 20*time.Second)
+	c.Assert(attrs.RedrivePolicy, DeepEquals, &sqs.RedrivePolicy{DeadLetterTargetArn: "arn:aws:sqs:us-east-1:123456789012:testQueue-dlq", MaxReceiveCount: 5})
+}
+
+func (s *S) TestQueueAttributesRoundTrip(c *C) {
+	attrs := &sqs.QueueAttributes{
+		VisibilityTimeout:         time.Minute,
+		ReceiveMessageWaitTime:    10 * time.Second,
+		RedrivePolicy:             &sqs.RedrivePolicy{DeadLetterTargetArn: "arn:aws:sqs:us-east-1:123456789012:testQueue-dlq", MaxReceiveCount: 3},
+		FifoQueue:                 true,
+		ContentBasedDeduplication: true,
+		QueueArn:                  "arn:aws:sqs:us-east-1:123456789012:testQueue.fifo",
+	}
+	wire, err := attrs.Attributes()
+	c.Assert(err, IsNil)
+	c.Assert(wire, DeepEquals, []sqs.Attribute{
+		{Name: "ReceiveMessageWaitTimeSeconds", Value: "10"},
+		{Name: "VisibilityTimeout", Value: "60"},
+		{Name: "RedrivePolicy", Value: `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:testQueue-dlq","maxReceiveCount":3}`},
+		{Name: "ContentBasedDeduplication", Value: "true"},
+	})
+
+	parsed, err := sqs.ParseQueueAttributes(wire)
+	c.Assert(err, IsNil)
+	attrs.QueueArn = ""
+	attrs.FifoQueue = false
+	c.Assert(parsed, DeepEquals, attrs)
+
+	parsed, err = sqs.ParseQueueAttributes([]sqs.Attribute{{Name: "Policy", Value: ""}, {Name: "RedrivePolicy", Value: ""}, {Name: "RedriveAllowPolicy", Value: ""}})
+	c.Assert(err, IsNil)
+	c.Assert(parsed, DeepEquals, &sqs.QueueAttributes{})
+
+	_, err = sqs.ParseQueueAttributes([]sqs.Attribute{{Name: "VisibilityTimeout", Value: "soon"}})
+	c.Assert(err, ErrorMatches, `sqs: invalid value "soon" for queue attribute VisibilityTimeout: .*`)
+}
+
+func (s *S) TestCreateQueueWithAttributes(c *C) {
+	testServer.PrepareResponse(200, nil, TestCreateQueueXmlOK)
+
+	q, err := s.sqs.CreateQueueWithAttributes("testQueue", &sqs.QueueAttributes{VisibilityTimeout: time.Minute}, map[string]string{"env": "test"})
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"CreateQueue"})
+	c.Assert(req.Form["QueueName"], DeepEquals, []string{"testQueue"})
+	c.Assert(req.Form["Tag.1.Key"], DeepEquals, []string{"env"})
+	c.Assert(err, IsNil)
+	c.Assert(q.Url, Equals, "http://sqs.us-east-1.amazonaws.com/123456789012/testQueue")
+}
+
+func (s *S) TestCreateFifoQueueWithAttributes(c *C) {
+	testServer.PrepareResponse(200, nil, TestCreateQueueXmlOK)
+
+	_, err := s.sqs.CreateQueueWithAttributes("testQueue.fifo", &sqs.QueueAttributes{FifoQueue: true, ContentBasedDeduplication: true}, nil)
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Attribute.1.Name"], DeepEquals, []string{"FifoQueue"})
+	c.Assert(req.Form["Attribute.1.Value"], DeepEquals, []string{"true"})
+	c.Assert(req.Form["Attribute.2.Name"], DeepEquals, []string{"ContentBasedDeduplication"})
+}
+
+func (s *S) TestFifoQueueAttributesGetSet(c *C) {
+	testServer.PrepareResponse(200, nil, TestGetQueueAttributesFifoXmlOK)
+	testServer.PrepareResponse(200, nil, TestSetQueueAttributesXmlOK)
+
+	q := s.fifoQueue()
+	attrs, err := q.Attributes()
+	testServer.WaitRequest()
+	c.Assert(err, IsNil)
+	c.Assert(attrs.FifoQueue, Equals, true)
+
+	_, err = q.SetAttributes(attrs)
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Action"], DeepEquals, []string{"SetQueueAttributes"})
+	var names []string
+	for i := 1; req.Form.Get("Attribute."+strconv.Itoa(i)+".Name") != ""; i++ {
+		names = append(names, req.Form.Get("Attribute."+strconv.Itoa(i)+".Name"))
+	}
+	c.Assert(names, DeepEquals, []string{
+		"MaximumMessageSize", "MessageRetentionPeriod", "VisibilityTimeout",
+		"SqsManagedSseEnabled", "ContentBasedDeduplication", "DeduplicationScope", "FifoThroughputLimit",
+	})
+}
+
+func (s *S) TestSetAttributes(c *C) {
+	testServer.PrepareResponse(200, nil, TestSetQueueAttributesXmlOK)
+
+	_, err := s.queue().SetAttributes(&sqs.QueueAttributes{DelaySeconds: 5 * time.Second, VisibilityTimeout: time.Minute})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Action"], DeepEquals, []string{"SetQueueAttributes"})
+	c.Assert(req.Form["Attribute.1.Name"], DeepEquals, []string{"DelaySeconds"})
+	c.Assert(req.Form["Attribute.1.Value"], DeepEquals, []string{"5"})
+	c.Assert(req.Form["Attribute.2.Name"], DeepEquals, []string{"VisibilityTimeout"})
+	c.Assert(req.Form["Attribute.2.Value"], DeepEquals, []string{"60"})
+}
+
+func (s *S) TestCreateQueueAttributeEncoding(c *C) {
+	testServer.PrepareResponse(200, nil, TestCreateQueueXmlOK)
+
+	_, err := s.sqs.CreateQueue("testQueue", []sqs.Attribute{{Name: "VisibilityTimeout", Value: "60"}, {Name: "DelaySeconds", Value: "5"}})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Attribute.1.Name"], DeepEquals, []string{"VisibilityTimeout"})
+	c.Assert(req.Form["Attribute.1.Value"], DeepEquals, []string{"60"})
+	c.Assert(req.Form["Attribute.2.Name"], DeepEquals, []string{"DelaySeconds"})
+	c.Assert(req.Form["Attribute.2.Value"], DeepEquals, []string{"5"})
+	c.Assert(req.Form["AttributeName.1.Name"], IsNil)
+}
+
+func (s *S) TestQueueAttributeValidation(c *C) {
+	q := s.queue()
+	_, err := q.SetQueueAttributes()
+	c.Assert(err, ErrorMatches, "sqs: SetQueueAttributes needs at least one attribute")
+	_, err = q.SetQueueAttributes(sqs.Attribute{Name: "VisibilityTimeout", Value: "43201"})
+	c.Assert(err, ErrorMatches, `sqs: queue attribute VisibilityTimeout must be an integer from 0 to 43200, not "43201"`)
+	_, err = q.SetQueueAttributes(sqs.Attribute{Name: "DelaySeconds", Value: "1"}, sqs.Attribute{Name: "DelaySeconds", Value: "2"})
+	c.Assert(err, ErrorMatches, "sqs: queue attribute DelaySeconds is given more than once")
+	_, err = q.SetQueueAttributes(sqs.Attribute{Name: "QueueArn", Value: "arn"})
+	c.Assert(err, ErrorMatches, "sqs: queue attribute QueueArn is read-only")
+	_, err = q.SetQueueAttributes(sqs.Attribute{Name: "VisibilityTimeOut", Value: "30"})
+	c.Assert(err, ErrorMatches, `sqs: unknown queue attribute "VisibilityTimeOut"`)
+	_, err = q.SetQueueAttributes(sqs.Attribute{Name: "DeduplicationScope", Value: "group"})
+	c.Assert(err, ErrorMatches, `sqs: queue attribute DeduplicationScope must be one of \[messageGroup queue\], not "group"`)
+	_, err = q.SetQueueAttributes(sqs.Attribute{Name: "RedrivePolicy", Value: `{"deadLetterTargetArn":"arn"}`})
+	c.Assert(err, NotNil)
+
+	_, err = s.sqs.CreateQueue("testQueue", []sqs.Attribute{{Name: "MaximumMessageSize", Value: "512"}})
+	c.Assert(err, ErrorMatches, "sqs: queue attribute MaximumMessageSize must be .*")
+}
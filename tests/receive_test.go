@@ -0,0 +1,71 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"net/http"
+	"sdk/sqs/sqs"
+	"time"
+)
+
+func (s *S) TestReceiveMessageLongPoll(c *C) {
+	testServer.PrepareResponse(200, nil, TestReceiveMessageXmlOK)
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	resp, err := q.ReceiveMessageWithParams(sqs.ReceiveMessageParams{
+		AttributeNames:      []sqs.MessageSystemAttributeName{sqs.SystemAttributeAll},
+		MaxNumberOfMessages: 5,
+		WaitTimeSeconds:     20,
+	})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Action"], DeepEquals, []string{"ReceiveMessage"})
+	c.Assert(req.Form["AttributeName.1"], DeepEquals, []string{"All"})
+	c.Assert(req.Form["MaxNumberOfMessages"], DeepEquals, []string{"5"})
+	c.Assert(req.Form["WaitTimeSeconds"], DeepEquals, []string{"20"})
+	c.Assert(req.Form["VisibilityTimeout"], IsNil)
+	c.Assert(resp.Messages, HasLen, 1)
+	c.Assert(resp.Messages[0].Body, Equals, "This is a test message")
+}
+
+func (s *S) TestReceiveMessageSendsZeros(c *C) {
+	testServer.PrepareResponse(200, nil, TestReceiveMessageXmlOK)
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	_, err := q.ReceiveMessage(nil, 10, 0)
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["MaxNumberOfMessages"], DeepEquals, []string{"10"})
+	c.Assert(req.Form["VisibilityTimeout"], DeepEquals, []string{"0"})
+}
+
+func (s *S) TestReceiveMessageExtendsClientTimeout(c *C) {
+	client := sqs.NewWithClient(s.sqs.Auth, s.sqs.Region, &http.Client{Timeout: 100 * time.Millisecond})
+	client.Retry = sqs.NoRetry
+	q := &sqs.Queue{SQS: client, Url: testServer.URL + "/123456789012/testQueue"}
+
+	done := make(chan error)
+	go func() {
+		_, err := q.ReceiveMessageWithParams(sqs.ReceiveMessageParams{WaitTimeSeconds: 1})
+		done <- err
+	}()
+	testServer.WaitRequest()
+	time.Sleep(300 * time.Millisecond)
+	testServer.PrepareResponse(200, nil, TestReceiveMessageXmlOK)
+
+	c.Assert(<-done, IsNil)
+}
+
+func (s *S) TestSetReceiveMessageWaitTimeSeconds(c *C) {
+	testServer.PrepareResponse(200, nil, TestSetQueueAttributesXmlOK)
+
+	q := &sqs.Queue{SQS: s.sqs, Url: testServer.URL + "/123456789012/testQueue"}
+	_, err := q.SetReceiveMessageWaitTimeSeconds(20)
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Action"], DeepEquals, []string{"SetQueueAttributes"})
+	c.Assert(req.Form["Attribute.1.Name"], DeepEquals, []string{"ReceiveMessageWaitTimeSeconds"})
+	c.Assert(req.Form["Attribute.1.Value"], DeepEquals, []string{"20"})
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+)
+
+func (s *S) TestRedrivePolicyAttribute(c *C) {
+	policy := &sqs.RedrivePolicy{DeadLetterTargetArn: "arn:aws:sqs:us-east-1:123456789012:testQueue-dlq", MaxReceiveCount: 5}
+	attribute, err := policy.Attribute()
+	c.Assert(err, IsNil)
+	c.Assert(attribute, Equals, sqs.Attribute{Name: "RedrivePolicy", Value: `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:testQueue-dlq","maxReceiveCount":5}`})
+
+	parsed, err := sqs.ParseRedrivePolicy(`{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:testQueue-dlq","maxReceiveCount":"5"}`)
+	c.Assert(err, IsNil)
+	c.Assert(parsed, DeepEquals, policy)
+
+	_, err = (&sqs.RedrivePolicy{DeadLetterTargetArn: "arn"}).Attribute()
+	c.Assert(err, NotNil)
+}
+
+func (s *S) TestRedriveAllowPolicyAttribute(c *C) {
+	policy := &sqs.RedriveAllowPolicy{
+		RedrivePermission: sqs.RedrivePermissionByQueue,
+		SourceQueueArns:   []string{"arn:aws:sqs:us-east-1:123456789012:testQueue"},
+	}
+	attribute, err := policy.Attribute()
+	c.Assert(err, IsNil)
+	c.Assert(attribute.Name, Equals, "RedriveAllowPolicy")
+
+	parsed, err := sqs.ParseRedriveAllowPolicy(attribute.Value)
+	c.Assert(err, IsNil)
+	c.Assert(parsed, DeepEquals, policy)
+
+	_, err = (&sqs.RedriveAllowPolicy{RedrivePermission: sqs.RedrivePermissionByQueue}).Attribute()
+	c.Assert(err, NotNil)
+	_, err = (&sqs.RedriveAllowPolicy{RedrivePermission: "sometimes"}).Attribute()
+	c.Assert(err, NotNil)
+}
+
+func (s *S) TestSetAndGetRedrivePolicy(c *C) {
+	testServer.PrepareResponse(200, nil, TestSetQueueAttributesXmlOK)
+	testServer.PrepareResponse(200, nil, TestGetQueueAttributesRedrivePolicyXmlOK)
+
+	q := s.queue()
+	_, err := q.SetRedrivePolicy(&sqs.RedrivePolicy{DeadLetterTargetArn: "arn:aws:sqs:us-east-1:123456789012:testQueue-dlq", MaxReceiveCount: 5})
+	req := testServer.WaitRequest()
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["Attribute.1.Name"], DeepEquals, []string{"RedrivePolicy"})
+
+	policy, err := q.RedrivePolicy()
+	req = testServer.WaitRequest()
+	c.Assert(err, IsNil)
+	c.Assert(req.Form["AttributeName.1"], DeepEquals, []string{"RedrivePolicy"})
+	c.Assert(policy.MaxReceiveCount, Equals, 5)
+	c.Assert(policy.DeadLetterTargetArn, Equals, "arn:aws:sqs:us-east-1:123456789012:testQueue-dlq")
+}
+
+func (s *S) TestDeadLetterSourceQueues(c *C) {
+	testServer.PrepareResponse(200, nil, TestListDeadLetterSourceQueuesPage1XmlOK)
+	testServer.PrepareResponse(200, nil, TestListDeadLetterSourceQueuesXmlOK)
+
+	queues, err := s.queue().DeadLetterSourceQueues()
+	first := testServer.WaitRequest()
+	second := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(first.Form["NextToken"], IsNil)
+	c.Assert(first.Form["MaxResults"], DeepEquals, []string{"1000"})
+	c.Assert(second.Form["NextToken"], DeepEquals, []string{"page2"})
+	c.Assert(queues, HasLen, 3)
+	c.Assert(queues[0].Url, Equals, "https://sqs.us-east-1.amazonaws.com/123456789012/SourceQueue1")
+	c.Assert(queues[2].Url, Equals, "https://sqs.us-east-1.amazonaws.com/123456789012/MySourceQueue")
+	c.Assert(queues[2].SQS, Equals, s.sqs)
+}
@@ -0,0 +1,628 @@
+package tests
+
+var TestCreateQueueXmlOK = `
+<CreateQueueResponse>
+  <CreateQueueResult>
+    <QueueUrl>http://sqs.us-east-1.amazonaws.com/123456789012/testQueue</QueueUrl>
+  </CreateQueueResult>
+  <ResponseMetadata>
+    <RequestId>7a62c49f-347e-4fc4-9331-6e8e7a96aa73</RequestId>
+  </ResponseMetadata>
+</CreateQueueResponse>
+`
+
+var TestListQueuesXmlOK = `
+<ListQueuesResponse>
+  <ListQueuesResult>
+    <QueueUrl>http://sqs.us-east-1.amazonaws.com/123456789012/testQueue</QueueUrl>
+  </ListQueuesResult>
+  <ResponseMetadata>
+    <RequestId>725275ae-0b9b-4762-b238-436d7c65a1ac</RequestId>
+  </ResponseMetadata>
+</ListQueuesResponse>
+`
+
+var TestSendMessageXmlOK = `
+<SendMessageResponse>
+  <SendMessageResult>
+    <MD5OfMessageBody>fafb00f5732ab283681e124bf8747ed1</MD5OfMessageBody>
+    <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+  </SendMessageResult>
+  <ResponseMetadata>
+    <RequestId>27daac76-34dd-47df-bd01-1f6e873584a0</RequestId>
+  </ResponseMetadata>
+</SendMessageResponse>
+`
+
+var TestServiceUnavailableXml = `
+<Response>
+  <Errors>
+    <Error>
+      <Code>ServiceUnavailable</Code>
+      <Message>Service is unable to handle request.</Message>
+    </Error>
+  </Errors>
+  <RequestID>0a6ff6ac-eb54-4c54-b1de-0e1d0a0c1c2f</RequestID>
+</Response>
+`
+
+var TestInvalidParameterValueXml = `
+<Response>
+  <Errors>
+    <Error>
+      <Code>InvalidParameterValue</Code>
+      <Message>Value (quename_nonalpha) for parameter QueueName is invalid.</Message>
+    </Error>
+  </Errors>
+  <RequestID>42d59b56-7407-4c4a-be0f-4c88daeea257</RequestID>
+</Response>
+`
+
+var TestReceiveMessageXmlOK = `
+<ReceiveMessageResponse>
+  <ReceiveMessageResult>
+    <Message>
+      <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+      <ReceiptHandle>MbZj6wDWli+JvwwJaBV+3dcjk2YW2vA3+STFFljTM8tJJg6HRG6PYSasuWXPJB+CwLj1FjgXUv1uSj1gUPAWV66FU/WeR4mq2OKpEGYWbnLmpRCJVAyeMjeU5ZBdtcQ+QEauMZc8ZRv37sIW2iJKq3M9MFx1YvV11A2x/KSbkJ0=</ReceiptHandle>
+      <MD5OfBody>fafb00f5732ab283681e124bf8747ed1</MD5OfBody>
+      <Body>This is a test message</Body>
+      <Attribute>
+        <Name>SenderId</Name>
+        <Value>195004372649</Value>
+      </Attribute>
+      <Attribute>
+        <Name>SentTimestamp</Name>
+        <Value>1238099229000</Value>
+      </Attribute>
+      <Attribute>
+        <Name>ApproximateReceiveCount</Name>
+        <Value>5</Value>
+      </Attribute>
+      <Attribute>
+        <Name>ApproximateFirstReceiveTimestamp</Name>
+        <Value>1250700979248</Value>
+      </Attribute>
+    </Message>
+  </ReceiveMessageResult>
+  <ResponseMetadata>
+    <RequestId>b6633655-283d-45b4-aee4-4e84e0ae6afa</RequestId>
+  </ResponseMetadata>
+</ReceiveMessageResponse>
+`
+
+var TestSetQueueAttributesXmlOK = `
+<SetQueueAttributesResponse>
+  <ResponseMetadata>
+    <RequestId>e5cca473-4fc0-4198-a451-8abb94d02c75</RequestId>
+  </ResponseMetadata>
+</SetQueueAttributesResponse>
+`
+
+var TestSendMessageWithAttributesXmlOK = `
+<SendMessageResponse>
+  <SendMessageResult>
+    <MD5OfMessageBody>fafb00f5732ab283681e124bf8747ed1</MD5OfMessageBody>
+    <MD5OfMessageAttributes>%s</MD5OfMessageAttributes>
+    <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+  </SendMessageResult>
+  <ResponseMetadata>
+    <RequestId>27daac76-34dd-47df-bd01-1f6e873584a0</RequestId>
+  </ResponseMetadata>
+</SendMessageResponse>
+`
+
+var TestReceiveMessageWithAttributesXmlOK = `
+<ReceiveMessageResponse>
+  <ReceiveMessageResult>
+    <Message>
+      <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+      <ReceiptHandle>MbZj6wDWli+JvwwJaBV+3dcjk2YW2vA3+STFFljTM8tJJg6HRG6PYSasuWXPJB+CwLj1FjgXUv1uSj1gUPAWV66FU/WeR4mq2OKpEGYWbnLmpRCJVAyeMjeU5ZBdtcQ+QEauMZc8ZRv37sIW2iJKq3M9MFx1YvV11A2x/KSbkJ0=</ReceiptHandle>
+      <MD5OfBody>fafb00f5732ab283681e124bf8747ed1</MD5OfBody>
+      <Body>This is a test message</Body>
+      <MD5OfMessageAttributes>%s</MD5OfMessageAttributes>
+      <MessageAttribute>
+        <Name>color</Name>
+        <Value>
+          <StringValue>blue</StringValue>
+          <DataType>String</DataType>
+        </Value>
+      </MessageAttribute>
+      <MessageAttribute>
+        <Name>payload</Name>
+        <Value>
+          <BinaryValue>aGVsbG8=</BinaryValue>
+          <DataType>Binary</DataType>
+        </Value>
+      </MessageAttribute>
+    </Message>
+  </ReceiveMessageResult>
+  <ResponseMetadata>
+    <RequestId>b6633655-283d-45b4-aee4-4e84e0ae6afa</RequestId>
+  </ResponseMetadata>
+</ReceiveMessageResponse>
+`
+
+var TestSendMessageFifoXmlOK = `
+<SendMessageResponse>
+  <SendMessageResult>
+    <MD5OfMessageBody>fafb00f5732ab283681e124bf8747ed1</MD5OfMessageBody>
+    <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+    <SequenceNumber>18849496460467696128</SequenceNumber>
+  </SendMessageResult>
+  <ResponseMetadata>
+    <RequestId>27daac76-34dd-47df-bd01-1f6e873584a0</RequestId>
+  </ResponseMetadata>
+</SendMessageResponse>
+`
+
+var TestSendMessageBatchXmlOK = `
+<SendMessageBatchResponse>
+  <SendMessageBatchResult>
+    <SendMessageBatchResultEntry>
+      <Id>test_msg_001</Id>
+      <MessageId>0a5231c7-8bff-4955-be2e-8dc7c50a25fa</MessageId>
+      <MD5OfMessageBody>0e024d309850c78cba5eabbeff7cae71</MD5OfMessageBody>
+    </SendMessageBatchResultEntry>
+    <SendMessageBatchResultEntry>
+      <Id>test_msg_002</Id>
+      <MessageId>15ee1ed3-87e7-40c1-bdaa-2e49968ea7e9</MessageId>
+      <MD5OfMessageBody>7fb8146a82f95e0af155278f406862c2</MD5OfMessageBody>
+    </SendMessageBatchResultEntry>
+  </SendMessageBatchResult>
+  <ResponseMetadata>
+    <RequestId>ca1ad5d0-8271-408b-8d0f-1351bf547e74</RequestId>
+  </ResponseMetadata>
+</SendMessageBatchResponse>
+`
+
+var TestNonExistentQueueXml = `
+<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>AWS.SimpleQueueService.NonExistentQueue</Code>
+    <Message>The specified queue does not exist for this wsdl version.</Message>
+    <Detail/>
+  </Error>
+  <RequestId>05c9f47a-7c5a-5d4f-9d7e-3a3b2c6a9b4e</RequestId>
+</ErrorResponse>
+`
+
+var TestPurgeQueueInProgressXml = `
+<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>AWS.SimpleQueueService.PurgeQueueInProgress</Code>
+    <Message>Only one PurgeQueue operation on testQueue is allowed every 60 seconds.</Message>
+    <Detail/>
+  </Error>
+  <RequestId>7a62c49f-347e-4fc4-9331-6e8e7a96aa73</RequestId>
+</ErrorResponse>
+`
+
+var TestPurgeQueueXmlOK = `
+<PurgeQueueResponse>
+  <ResponseMetadata>
+    <RequestId>6fde8d1e-52cd-4581-8cd9-c512f4c64223</RequestId>
+  </ResponseMetadata>
+</PurgeQueueResponse>
+`
+
+var TestListDeadLetterSourceQueuesXmlOK = `
+<ListDeadLetterSourceQueuesResponse>
+  <ListDeadLetterSourceQueuesResult>
+    <QueueUrl>https://sqs.us-east-1.amazonaws.com/123456789012/MySourceQueue</QueueUrl>
+  </ListDeadLetterSourceQueuesResult>
+  <ResponseMetadata>
+    <RequestId>8ffb921f-b85e-53d9-abcf-d8d0057f38fc</RequestId>
+  </ResponseMetadata>
+</ListDeadLetterSourceQueuesResponse>
+`
+
+var TestDeleteMessageBatchXmlOK = `
+<DeleteMessageBatchResponse>
+  <DeleteMessageBatchResult>
+    <DeleteMessageBatchResultEntry>
+      <Id>msg1</Id>
+    </DeleteMessageBatchResultEntry>
+    <DeleteMessageBatchResultEntry>
+      <Id>msg2</Id>
+    </DeleteMessageBatchResultEntry>
+  </DeleteMessageBatchResult>
+  <ResponseMetadata>
+    <RequestId>d6f86b7a-74d1-4439-b43f-196a1e29cd85</RequestId>
+  </ResponseMetadata>
+</DeleteMessageBatchResponse>
+`
+
+var TestSendMessageBatchPartialXmlOK = `
+<SendMessageBatchResponse>
+  <SendMessageBatchResult>
+    <SendMessageBatchResultEntry>
+      <Id>test_msg_001</Id>
+      <MessageId>0a5231c7-8bff-4955-be2e-8dc7c50a25fa</MessageId>
+      <MD5OfMessageBody>0e024d309850c78cba5eabbeff7cae71</MD5OfMessageBody>
+    </SendMessageBatchResultEntry>
+    <BatchResultErrorEntry>
+      <Id>test_msg_002</Id>
+      <Code>InternalError</Code>
+      <Message>We encountered an internal error. Please try again.</Message>
+      <SenderFault>false</SenderFault>
+    </BatchResultErrorEntry>
+  </SendMessageBatchResult>
+  <ResponseMetadata>
+    <RequestId>ca1ad5d0-8271-408b-8d0f-1351bf547e74</RequestId>
+  </ResponseMetadata>
+</SendMessageBatchResponse>
+`
+
+var TestChangeMessageVisibilityBatchPartialXmlOK = `
+<ChangeMessageVisibilityBatchResponse>
+  <ChangeMessageVisibilityBatchResult>
+    <ChangeMessageVisibilityBatchResultEntry>
+      <Id>change_visibility_msg_2</Id>
+    </ChangeMessageVisibilityBatchResultEntry>
+    <BatchResultErrorEntry>
+      <Id>change_visibility_msg_3</Id>
+      <Code>ReceiptHandleIsInvalid</Code>
+      <Message>The input receipt handle is invalid.</Message>
+      <SenderFault>true</SenderFault>
+    </BatchResultErrorEntry>
+  </ChangeMessageVisibilityBatchResult>
+  <ResponseMetadata>
+    <RequestId>ca9668f7-ab1b-4f7a-8859-f15747ab17a7</RequestId>
+  </ResponseMetadata>
+</ChangeMessageVisibilityBatchResponse>
+`
+
+var TestMultipleErrorsXml = `
+<Response>
+  <Errors>
+    <Error>
+      <Code>InvalidParameterValue</Code>
+      <Message>Value for parameter MaxNumberOfMessages is invalid.</Message>
+    </Error>
+    <Error>
+      <Code>RequestThrottled</Code>
+      <Message>Rate exceeded.</Message>
+    </Error>
+  </Errors>
+  <RequestID>c3f4b0e5-3bd1-4ec2-9ed0-96e9e3a1f6e2</RequestID>
+</Response>
+`
+
+var TestListDeadLetterSourceQueuesPage1XmlOK = `
+<ListDeadLetterSourceQueuesResponse>
+  <ListDeadLetterSourceQueuesResult>
+    <QueueUrl>https://sqs.us-east-1.amazonaws.com/123456789012/SourceQueue1</QueueUrl>
+    <QueueUrl>https://sqs.us-east-1.amazonaws.com/123456789012/SourceQueue2</QueueUrl>
+    <NextToken>page2</NextToken>
+  </ListDeadLetterSourceQueuesResult>
+  <ResponseMetadata>
+    <RequestId>8ffb921f-b85e-53d9-abcf-d8d0057f38fc</RequestId>
+  </ResponseMetadata>
+</ListDeadLetterSourceQueuesResponse>
+`
+
+var TestGetQueueAttributesRedrivePolicyXmlOK = `
+<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute>
+      <Name>RedrivePolicy</Name>
+      <Value>{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:testQueue-dlq","maxReceiveCount":5}</Value>
+    </Attribute>
+  </GetQueueAttributesResult>
+  <ResponseMetadata>
+    <RequestId>1ea71be5-b5a2-4f9d-b85a-945d8d08cd0b</RequestId>
+  </ResponseMetadata>
+</GetQueueAttributesResponse>
+`
+
+var TestGetQueueAttributesQueueArnXmlOK = `
+<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute>
+      <Name>QueueArn</Name>
+      <Value>%s</Value>
+    </Attribute>
+  </GetQueueAttributesResult>
+  <ResponseMetadata>
+    <RequestId>1ea71be5-b5a2-4f9d-b85a-945d8d08cd0b</RequestId>
+  </ResponseMetadata>
+</GetQueueAttributesResponse>
+`
+
+var TestStartMessageMoveTaskXmlOK = `
+<StartMessageMoveTaskResponse>
+  <StartMessageMoveTaskResult>
+    <TaskHandle>eyJ0YXNrSWQiOiJkYzE2OWUwNC0wZTU1LTQ0ZDItYWE5MC1jMDgwY2ExZjM2ZjciLCJzb3VyY2VBcm4iOiJhcm46YXdzOnNxczp1cy1lYXN0LTE6MTIzNDU2Nzg5MDEyOnRlc3RRdWV1ZS1kbHEifQ==</TaskHandle>
+  </StartMessageMoveTaskResult>
+  <ResponseMetadata>
+    <RequestId>d8c6a9a4-2b3e-5c8f-9a45-3c2b1e5f7a61</RequestId>
+  </ResponseMetadata>
+</StartMessageMoveTaskResponse>
+`
+
+var TestListMessageMoveTasksXmlOK = `
+<ListMessageMoveTasksResponse>
+  <ListMessageMoveTasksResult>
+    <ListMessageMoveTasksResultEntry>
+      <TaskHandle>eyJ0YXNrSWQiOiJkYzE2OWUwNC0wZTU1LTQ0ZDItYWE5MC1jMDgwY2ExZjM2ZjciLCJzb3VyY2VBcm4iOiJhcm46YXdzOnNxczp1cy1lYXN0LTE6MTIzNDU2Nzg5MDEyOnRlc3RRdWV1ZS1kbHEifQ==</TaskHandle>
+      <Status>RUNNING</Status>
+      <SourceArn>arn:aws:sqs:us-east-1:123456789012:testQueue-dlq</SourceArn>
+      <MaxNumberOfMessagesPerSecond>50</MaxNumberOfMessagesPerSecond>
+      <ApproximateNumberOfMessagesMoved>203</ApproximateNumberOfMessagesMoved>
+      <ApproximateNumberOfMessagesToMove>30</ApproximateNumberOfMessagesToMove>
+      <StartedTimestamp>1442428276921</StartedTimestamp>
+    </ListMessageMoveTasksResultEntry>
+    <ListMessageMoveTasksResultEntry>
+      <Status>FAILED</Status>
+      <SourceArn>arn:aws:sqs:us-east-1:123456789012:testQueue-dlq</SourceArn>
+      <DestinationArn>arn:aws:sqs:us-east-1:123456789012:otherQueue</DestinationArn>
+      <ApproximateNumberOfMessagesMoved>17</ApproximateNumberOfMessagesMoved>
+      <ApproximateNumberOfMessagesToMove>120</ApproximateNumberOfMessagesToMove>
+      <FailureReason>AWS.SimpleQueueService.NonExistentQueue</FailureReason>
+      <StartedTimestamp>1442428276000</StartedTimestamp>
+    </ListMessageMoveTasksResultEntry>
+  </ListMessageMoveTasksResult>
+  <ResponseMetadata>
+    <RequestId>0f5b2c3d-7e8a-5b6c-8d9e-1a2b3c4d5e6f</RequestId>
+  </ResponseMetadata>
+</ListMessageMoveTasksResponse>
+`
+
+var TestCancelMessageMoveTaskXmlOK = `
+<CancelMessageMoveTaskResponse>
+  <CancelMessageMoveTaskResult>
+    <ApproximateNumberOfMessagesMoved>203</ApproximateNumberOfMessagesMoved>
+  </CancelMessageMoveTaskResult>
+  <ResponseMetadata>
+    <RequestId>6b8c9d0e-1f2a-5b3c-9d4e-5f6a7b8c9d0e</RequestId>
+  </ResponseMetadata>
+</CancelMessageMoveTaskResponse>
+`
+
+var TestTagQueueXmlOK = `
+<TagQueueResponse>
+  <ResponseMetadata>
+    <RequestId>a1b2c3d4-e5f6-5a7b-8c9d-0e1f2a3b4c5d</RequestId>
+  </ResponseMetadata>
+</TagQueueResponse>
+`
+
+var TestUntagQueueXmlOK = `
+<UntagQueueResponse>
+  <ResponseMetadata>
+    <RequestId>b2c3d4e5-f6a7-5b8c-9d0e-1f2a3b4c5d6e</RequestId>
+  </ResponseMetadata>
+</UntagQueueResponse>
+`
+
+var TestListQueueTagsXmlOK = `
+<ListQueueTagsResponse>
+  <ListQueueTagsResult>
+    <Tag>
+      <Key>team</Key>
+      <Value>payments</Value>
+    </Tag>
+    <Tag>
+      <Key>env</Key>
+      <Value>staging</Value>
+    </Tag>
+  </ListQueueTagsResult>
+  <ResponseMetadata>
+    <RequestId>c3d4e5f6-a7b8-5c9d-0e1f-2a3b4c5d6e7f</RequestId>
+  </ResponseMetadata>
+</ListQueueTagsResponse>
+`
+
+var TestListQueuesPage1XmlOK = `
+<ListQueuesResponse>
+  <ListQueuesResult>
+    <QueueUrl>http://sqs.us-east-1.amazonaws.com/123456789012/queue1</QueueUrl>
+    <QueueUrl>http://sqs.us-east-1.amazonaws.com/123456789012/queue2</QueueUrl>
+    <NextToken>AQICAHhNZXh0VG9rZW4tcGFnZTI=</NextToken>
+  </ListQueuesResult>
+  <ResponseMetadata>
+    <RequestId>725275ae-0b9b-4762-b238-436d7c65a1ac</RequestId>
+  </ResponseMetadata>
+</ListQueuesResponse>
+`
+
+var TestListQueuesPage2XmlOK = `
+<ListQueuesResponse>
+  <ListQueuesResult>
+    <QueueUrl>http://sqs.us-east-1.amazonaws.com/123456789012/queue3</QueueUrl>
+  </ListQueuesResult>
+  <ResponseMetadata>
+    <RequestId>8d1e2f3a-4b5c-5d6e-7f8a-9b0c1d2e3f4a</RequestId>
+  </ResponseMetadata>
+</ListQueuesResponse>
+`
+
+var TestGetQueueAttributesAllXmlOK = `
+<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute>
+      <Name>QueueArn</Name>
+      <Value>arn:aws:sqs:us-east-1:123456789012:testQueue</Value>
+    </Attribute>
+    <Attribute>
+      <Name>ApproximateNumberOfMessages</Name>
+      <Value>12</Value>
+    </Attribute>
+    <Attribute>
+      <Name>ApproximateNumberOfMessagesNotVisible</Name>
+      <Value>3</Value>
+    </Attribute>
+    <Attribute>
+      <Name>ApproximateNumberOfMessagesDelayed</Name>
+      <Value>0</Value>
+    </Attribute>
+    <Attribute>
+      <Name>CreatedTimestamp</Name>
+      <Value>1286771522</Value>
+    </Attribute>
+    <Attribute>
+      <Name>LastModifiedTimestamp</Name>
+      <Value>1286771600</Value>
+    </Attribute>
+    <Attribute>
+      <Name>VisibilityTimeout</Name>
+      <Value>30</Value>
+    </Attribute>
+    <Attribute>
+      <Name>MaximumMessageSize</Name>
+      <Value>262144</Value>
+    </Attribute>
+    <Attribute>
+      <Name>MessageRetentionPeriod</Name>
+      <Value>345600</Value>
+    </Attribute>
+    <Attribute>
+      <Name>DelaySeconds</Name>
+      <Value>0</Value>
+    </Attribute>
+    <Attribute>
+      <Name>ReceiveMessageWaitTimeSeconds</Name>
+      <Value>20</Value>
+    </Attribute>
+    <Attribute>
+      <Name>RedrivePolicy</Name>
+      <Value>{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:testQueue-dlq","maxReceiveCount":5}</Value>
+    </Attribute>
+  </GetQueueAttributesResult>
+  <ResponseMetadata>
+    <RequestId>1ea71be5-b5a2-4f9d-b85a-945d8d08cd0b</RequestId>
+  </ResponseMetadata>
+</GetQueueAttributesResponse>
+`
+
+var TestListQueuesLocalXmlOK = `
+<ListQueuesResponse>
+  <ListQueuesResult>
+    <QueueUrl>%[1]s/123456789012/queue1</QueueUrl>
+    <QueueUrl>%[1]s/123456789012/queue2</QueueUrl>
+    <QueueUrl>%[1]s/123456789012/queue3</QueueUrl>
+    <QueueUrl>%[1]s/123456789012/queue4</QueueUrl>
+  </ListQueuesResult>
+  <ResponseMetadata>
+    <RequestId>725275ae-0b9b-4762-b238-436d7c65a1ac</RequestId>
+  </ResponseMetadata>
+</ListQueuesResponse>
+`
+
+var TestGetQueueAttributesKmsXmlOK = `
+<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute>
+      <Name>KmsMasterKeyId</Name>
+      <Value>alias/aws/sqs</Value>
+    </Attribute>
+  </GetQueueAttributesResult>
+  <ResponseMetadata>
+    <RequestId>1ea71be5-b5a2-4f9d-b85a-945d8d08cd0b</RequestId>
+  </ResponseMetadata>
+</GetQueueAttributesResponse>
+`
+
+var TestGetQueueAttributesSqsManagedSseXmlOK = `
+<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute>
+      <Name>SqsManagedSseEnabled</Name>
+      <Value>true</Value>
+    </Attribute>
+  </GetQueueAttributesResult>
+  <ResponseMetadata>
+    <RequestId>1ea71be5-b5a2-4f9d-b85a-945d8d08cd0b</RequestId>
+  </ResponseMetadata>
+</GetQueueAttributesResponse>
+`
+
+var TestGetQueueAttributesUnencryptedXmlOK = `
+<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute>
+      <Name>SqsManagedSseEnabled</Name>
+      <Value>false</Value>
+    </Attribute>
+  </GetQueueAttributesResult>
+  <ResponseMetadata>
+    <RequestId>1ea71be5-b5a2-4f9d-b85a-945d8d08cd0b</RequestId>
+  </ResponseMetadata>
+</GetQueueAttributesResponse>
+`
+
+var TestReceiveMessageCorruptedXmlOK = `
+<ReceiveMessageResponse>
+  <ReceiveMessageResult>
+    <Message>
+      <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+      <ReceiptHandle>MbZj6wDWli+JvwwJaBV+3dcjk2YW2vA3+STFFljTM8tJJg6HRG6PYSasuWXPJB+CwLj1FjgXUv1uSj1gUPAWV66FU/WeR4mq2OKpEGYWbnLmpRCJVAyeMjeU5ZBdtcQ+QEauMZc8ZRv37sIW2iJKq3M9MFx1YvV11A2x/KSbkJ0=</ReceiptHandle>
+      <MD5OfBody>fafb00f5732ab283681e124bf8747ed1</MD5OfBody>
+      <Body>This is a test massage</Body>
+    </Message>
+  </ReceiveMessageResult>
+  <ResponseMetadata>
+    <RequestId>b6633655-283d-45b4-aee4-4e84e0ae6afa</RequestId>
+  </ResponseMetadata>
+</ReceiveMessageResponse>
+`
+
+var TestGetQueueAttributesFifoXmlOK = `
+<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute>
+      <Name>QueueArn</Name>
+      <Value>arn:aws:sqs:us-east-1:123456789012:testQueue.fifo</Value>
+    </Attribute>
+    <Attribute>
+      <Name>ApproximateNumberOfMessages</Name>
+      <Value>0</Value>
+    </Attribute>
+    <Attribute>
+      <Name>CreatedTimestamp</Name>
+      <Value>1286771522</Value>
+    </Attribute>
+    <Attribute>
+      <Name>DelaySeconds</Name>
+      <Value>0</Value>
+    </Attribute>
+    <Attribute>
+      <Name>MaximumMessageSize</Name>
+      <Value>262144</Value>
+    </Attribute>
+    <Attribute>
+      <Name>MessageRetentionPeriod</Name>
+      <Value>345600</Value>
+    </Attribute>
+    <Attribute>
+      <Name>VisibilityTimeout</Name>
+      <Value>30</Value>
+    </Attribute>
+    <Attribute>
+      <Name>SqsManagedSseEnabled</Name>
+      <Value>true</Value>
+    </Attribute>
+    <Attribute>
+      <Name>FifoQueue</Name>
+      <Value>true</Value>
+    </Attribute>
+    <Attribute>
+      <Name>ContentBasedDeduplication</Name>
+      <Value>true</Value>
+    </Attribute>
+    <Attribute>
+      <Name>DeduplicationScope</Name>
+      <Value>messageGroup</Value>
+    </Attribute>
+    <Attribute>
+      <Name>FifoThroughputLimit</Name>
+      <Value>perMessageGroupId</Value>
+    </Attribute>
+  </GetQueueAttributesResult>
+  <ResponseMetadata>
+    <RequestId>b5293cb5-d306-4a17-9048-b263635abe42</RequestId>
+  </ResponseMetadata>
+</GetQueueAttributesResponse>
+`
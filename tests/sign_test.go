@@ -0,0 +1,79 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"strings"
+)
+
+func (s *S) TestSignatureV4(c *C) {
+	testServer.PrepareResponse(200, nil, TestListQueuesXmlOK)
+
+	resp, err := s.sqs.ListQueues()
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(resp.QueueUrl, DeepEquals, []string{"http://sqs.us-east-1.amazonaws.com/123456789012/testQueue"})
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.Form["Action"], DeepEquals, []string{"ListQueues"})
+	c.Assert(req.Header.Get("X-Amz-Date"), Matches, "[0-9]{8}T[0-9]{6}Z")
+
+	auth := req.Header.Get("Authorization")
+	c.Assert(strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=abc/"), Equals, true)
+	c.Assert(strings.Contains(auth, "/us-west-2/sqs/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature="), Equals, true)
+	c.Assert(req.Form["X-Amz-Signature"], IsNil)
+	c.Assert(req.Form["Signature"], IsNil)
+}
+
+func (s *S) TestSignatureV4Presigned(c *C) {
+	get := sqs.New(s.sqs.Auth, s.sqs.Region)
+	get.Method = "GET"
+	testServer.PrepareResponse(200, nil, TestListQueuesXmlOK)
+
+	_, err := get.ListQueues()
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.Header.Get("Authorization"), Equals, "")
+	c.Assert(req.Form["X-Amz-Algorithm"], DeepEquals, []string{"AWS4-HMAC-SHA256"})
+	c.Assert(req.Form["X-Amz-SignedHeaders"], DeepEquals, []string{"host"})
+	c.Assert(strings.HasPrefix(req.Form.Get("X-Amz-Credential"), "abc/"), Equals, true)
+	c.Assert(strings.HasSuffix(req.Form.Get("X-Amz-Credential"), "/us-west-2/sqs/aws4_request"), Equals, true)
+	c.Assert(req.Form.Get("X-Amz-Signature"), Matches, "[0-9a-f]{64}")
+	c.Assert(req.Form["Signature"], IsNil)
+}
+
+func (s *S) TestSignatureV2(c *C) {
+	v2 := sqs.New(s.sqs.Auth, s.sqs.Region)
+	v2.SignatureVersion = sqs.SignatureV2
+	testServer.PrepareResponse(200, nil, TestListQueuesXmlOK)
+
+	_, err := v2.ListQueues()
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.Form["SignatureVersion"], DeepEquals, []string{"2"})
+	c.Assert(req.Form["AWSAccessKeyId"], DeepEquals, []string{"abc"})
+	c.Assert(req.Form["Signature"], NotNil)
+	c.Assert(req.Form["Timestamp"], NotNil)
+	c.Assert(req.Form["X-Amz-Signature"], IsNil)
+}
+
+func (s *S) TestQueueUrlShorterThanEndpoint(c *C) {
+	region := s.sqs.Region
+	region.SQSEndpoint = testServer.URL + "/a/longer/endpoint/than/the/queue/url"
+	for _, version := range []int{sqs.SignatureV4, sqs.SignatureV2} {
+		client := sqs.New(s.sqs.Auth, region)
+		client.SignatureVersion = version
+		testServer.PrepareResponse(200, nil, TestSetQueueAttributesXmlOK)
+
+		q := &sqs.Queue{SQS: client, Url: testServer.URL + "/1/q"}
+		_, err := q.SetQueueAttributes(sqs.Attribute{Name: "VisibilityTimeout", Value: "30"})
+		req := testServer.WaitRequest()
+
+		c.Assert(err, IsNil)
+		c.Assert(req.URL.Path, Equals, "/1/q")
+	}
+}
@@ -298,22 +298,9 @@ func (self *SqsSimpleTestSuite) TestSetQueueAttributes(c *gocheck.C) {
 	q, err := self.sqs.CreateQueue(qName,[]sqs.Attribute{})
 	defer self.deleteQueue(qName)
 
-	var policyStr = `
-	  {
-			"Version":"2008-10-17",
-			"Id":"/123456789012/TestSetQueueAttributes/SQSDefaultPolicy",
-			"Statement":  [
-				 {
-				 "Sid":"Queue1ReceiveMessage",
-				 "Effect":"Allow",
-				 "Principal":{"AWS":"*"},
-				 "Action":"SQS:ReceiveMessage",
-				 "Resource":"arn:aws:sqs:us-east-1:123456789012:testQueue"
-				  }
-			 ]
-	   }
-  `
-	_, err = q.SetQueueAttributes(sqs.Attribute{"Policy", policyStr})
+	policy := sqs.NewPolicy("/123456789012/TestSetQueueAttributes/SQSDefaultPolicy")
+	policy.Allow("Queue1ReceiveMessage", sqs.AnyPrincipal(), "arn:aws:sqs:us-east-1:123456789012:testQueue", "SQS:ReceiveMessage")
+	_, err = q.SetPolicy(policy)
 
 	c.Assert(err, gocheck.IsNil)
 }
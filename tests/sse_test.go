@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"fmt"
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"time"
+)
+
+func (s *S) TestEncryptionAttributes(c *C) {
+	attrs := &sqs.QueueAttributes{KmsMasterKeyId: "alias/aws/sqs", KmsDataKeyReusePeriod: time.Hour}
+	wire, err := attrs.Attributes()
+	c.Assert(err, IsNil)
+	c.Assert(wire, DeepEquals, []sqs.Attribute{
+		{Name: "KmsMasterKeyId", Value: "alias/aws/sqs"},
+		{Name: "KmsDataKeyReusePeriodSeconds", Value: "3600"},
+	})
+	c.Assert(attrs.Encryption(), Equals, sqs.EncryptionKMS)
+	c.Assert((&sqs.QueueAttributes{SqsManagedSseEnabled: true}).Encryption(), Equals, sqs.EncryptionSQS)
+	c.Assert((&sqs.QueueAttributes{}).Encrypted(), Equals, false)
+
+	q := s.queue()
+	_, err = q.SetAttributes(&sqs.QueueAttributes{KmsMasterKeyId: "alias/aws/sqs", SqsManagedSseEnabled: true})
+	c.Assert(err, ErrorMatches, "sqs: queue attributes KmsMasterKeyId and SqsManagedSseEnabled cannot be used together")
+	_, err = q.SetAttributes(&sqs.QueueAttributes{KmsDataKeyReusePeriod: time.Second})
+	c.Assert(err, ErrorMatches, "sqs: queue attribute KmsDataKeyReusePeriodSeconds must be .*")
+}
+
+func (s *S) TestQueueEncryption(c *C) {
+	testServer.PrepareResponse(200, nil, TestGetQueueAttributesSqsManagedSseXmlOK)
+
+	encryption, err := s.queue().Encryption()
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["AttributeName.1"], DeepEquals, []string{"KmsMasterKeyId"})
+	c.Assert(req.Form["AttributeName.2"], DeepEquals, []string{"SqsManagedSseEnabled"})
+	c.Assert(err, IsNil)
+	c.Assert(encryption, Equals, sqs.EncryptionSQS)
+}
+
+func (s *S) TestUnencryptedQueues(c *C) {
+	testServer.PrepareResponse(200, nil, fmt.Sprintf(TestListQueuesLocalXmlOK, testServer.URL))
+	testServer.PrepareResponse(200, nil, TestGetQueueAttributesKmsXmlOK)
+	testServer.PrepareResponse(200, nil, TestGetQueueAttributesUnencryptedXmlOK)
+	testServer.PrepareResponse(400, nil, TestNonExistentQueueXml)
+	testServer.PrepareResponse(200, nil, TestGetQueueAttributesSqsManagedSseXmlOK)
+
+	queues, err := s.sqs.UnencryptedQueues("")
+	for i := 0; i < 5; i++ {
+		testServer.WaitRequest()
+	}
+
+	c.Assert(err, IsNil)
+	c.Assert(queues, HasLen, 1)
+	c.Assert(queues[0].Url, Equals, testServer.URL+"/123456789012/queue2")
+}
@@ -87,6 +87,9 @@ func (s *TestHTTPServer) FlushRequests() {
 
 func (s *TestHTTPServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	log.Println(req)
+	// Parse the form while the body is still readable; WaitRequest may
+	// only get to the request once the response has been written.
+	req.ParseForm()
 	s.request <- req
 	var resp *testResponse
 	select {
@@ -0,0 +1,39 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+	"sdk/sqs/sqs"
+	"time"
+)
+
+func (s *S) TestMessageSystemAttributes(c *C) {
+	testServer.PrepareResponse(200, nil, TestReceiveMessageXmlOK)
+
+	resp, err := s.queue().ReceiveMessageWithParams(sqs.ReceiveMessageParams{
+		AttributeNames: []sqs.MessageSystemAttributeName{sqs.SystemAttributeSentTimestamp, sqs.SystemAttributeApproximateReceiveCount},
+	})
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["AttributeName.1"], DeepEquals, []string{"SentTimestamp"})
+	c.Assert(req.Form["AttributeName.2"], DeepEquals, []string{"ApproximateReceiveCount"})
+	c.Assert(err, IsNil)
+
+	m := &resp.Messages[0]
+	c.Assert(m.SenderId(), Equals, "195004372649")
+	c.Assert(m.SentTimestamp().Equal(time.Unix(1238099229, 0)), Equals, true)
+	c.Assert(m.ApproximateReceiveCount(), Equals, 5)
+	c.Assert(m.ApproximateFirstReceiveTimestamp().Equal(time.Unix(1250700979, 248000000)), Equals, true)
+	c.Assert(m.MessageGroupId(), Equals, "")
+
+	_, ok := m.SystemAttribute(sqs.SystemAttributeSequenceNumber)
+	c.Assert(ok, Equals, false)
+	value, ok := m.SystemAttribute(sqs.SystemAttributeSenderId)
+	c.Assert(ok, Equals, true)
+	c.Assert(value, Equals, "195004372649")
+}
+
+func (s *S) TestMessageSystemAttributesMissing(c *C) {
+	m := &sqs.Message{Attribute: []sqs.Attribute{{Name: "SentTimestamp", Value: "yesterday"}}}
+	c.Assert(m.SentTimestamp().IsZero(), Equals, true)
+	c.Assert(m.ApproximateReceiveCount(), Equals, 0)
+}
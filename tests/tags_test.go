@@ -0,0 +1,56 @@
+package tests
+
+import (
+	. "launchpad.net/gocheck"
+)
+
+func (s *S) TestCreateQueueWithTags(c *C) {
+	testServer.PrepareResponse(200, nil, TestCreateQueueXmlOK)
+
+	q, err := s.sqs.CreateQueueWithTags("testQueue", nil, map[string]string{"team": "payments", "env": "staging"})
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"CreateQueue"})
+	c.Assert(req.Form["Tag.1.Key"], DeepEquals, []string{"env"})
+	c.Assert(req.Form["Tag.1.Value"], DeepEquals, []string{"staging"})
+	c.Assert(req.Form["Tag.2.Key"], DeepEquals, []string{"team"})
+	c.Assert(req.Form["Tag.2.Value"], DeepEquals, []string{"payments"})
+	c.Assert(err, IsNil)
+	c.Assert(q.Url, Equals, "http://sqs.us-east-1.amazonaws.com/123456789012/testQueue")
+}
+
+func (s *S) TestTagQueue(c *C) {
+	testServer.PrepareResponse(200, nil, TestTagQueueXmlOK)
+
+	_, err := s.queue().TagQueue(map[string]string{"cost-center": "42"})
+	req := testServer.WaitRequest()
+
+	c.Assert(req.URL.Path, Equals, "/123456789012/testQueue")
+	c.Assert(req.Form["Action"], DeepEquals, []string{"TagQueue"})
+	c.Assert(req.Form["Tag.1.Key"], DeepEquals, []string{"cost-center"})
+	c.Assert(req.Form["Tag.1.Value"], DeepEquals, []string{"42"})
+	c.Assert(err, IsNil)
+}
+
+func (s *S) TestUntagQueue(c *C) {
+	testServer.PrepareResponse(200, nil, TestUntagQueueXmlOK)
+
+	_, err := s.queue().UntagQueue([]string{"team", "env"})
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"UntagQueue"})
+	c.Assert(req.Form["TagKey.1"], DeepEquals, []string{"team"})
+	c.Assert(req.Form["TagKey.2"], DeepEquals, []string{"env"})
+	c.Assert(err, IsNil)
+}
+
+func (s *S) TestListQueueTags(c *C) {
+	testServer.PrepareResponse(200, nil, TestListQueueTagsXmlOK)
+
+	resp, err := s.queue().ListQueueTags()
+	req := testServer.WaitRequest()
+
+	c.Assert(req.Form["Action"], DeepEquals, []string{"ListQueueTags"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.Tags(), DeepEquals, map[string]string{"team": "payments", "env": "staging"})
+}